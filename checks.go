@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/health"
+	"github.com/yesetoda/Sera_Ale/internal/migrate"
+	"github.com/yesetoda/Sera_Ale/internal/service"
+	"github.com/yesetoda/Sera_Ale/migrations"
+)
+
+// storageCheckTTL is how long a storage check result is reused. Cloudinary's
+// Admin API is rate limited per hour, and every replica probes readiness.
+const storageCheckTTL = time.Minute
+
+// registerChecks wires the readiness checks for every dependency a request
+// may touch
+func registerChecks(checker *health.Checker, db *sql.DB, bootstrap app.BootstrapApp, storage service.CloudinaryService) error {
+	runner, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	checker.Add("database", db.PingContext)
+	checker.Add("migrations", func(ctx context.Context) error {
+		pending, err := runner.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migration(s), next is %03d_%s", len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
+	})
+	checker.Add("seed", func(ctx context.Context) error {
+		status, err := bootstrap.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if !status.Complete {
+			return errors.New("seed roles are missing")
+		}
+		return nil
+	})
+	checker.Add("storage", health.Cached(storage.Ping, storageCheckTTL))
+	return nil
+}
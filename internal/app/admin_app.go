@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+var (
+	ErrUserNotFound       = errors.New("User not found")
+	ErrCannotSuspendAdmin = errors.New("Admin accounts cannot be suspended")
+	ErrJobAlreadyRemoved  = errors.New("Job already taken down")
+)
+
+type AdminApp interface {
+	ListUsers(ctx context.Context, actor policy.Actor, filters map[string]interface{}, q domain.PageQuery) ([]domain.User, domain.PageInfo, error)
+	SuspendUser(ctx context.Context, actor policy.Actor, userID string) (*domain.User, error)
+	ReactivateUser(ctx context.Context, actor policy.Actor, userID string) (*domain.User, error)
+	TakeDownJob(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error)
+	Stats(ctx context.Context, actor policy.Actor) (*domain.PlatformStats, error)
+}
+
+type adminApp struct {
+	users   repository.UserRepository
+	jobs    repository.JobRepository
+	appRepo repository.ApplicationRepository
+	tx      repository.Transactor
+	events  EventPublisher
+}
+
+func NewAdminApp(users repository.UserRepository, jobs repository.JobRepository, appRepo repository.ApplicationRepository, tx repository.Transactor, events EventPublisher) AdminApp {
+	return &adminApp{users: users, jobs: jobs, appRepo: appRepo, tx: tx, events: events}
+}
+
+func (a *adminApp) ListUsers(ctx context.Context, actor policy.Actor, filters map[string]interface{}, q domain.PageQuery) ([]domain.User, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "AdminApp.ListUsers")
+	defer span.End()
+	if err := authorize(actor, policy.UserManage, policy.Resource{}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.users.List(ctx, filters, q)
+}
+
+func (a *adminApp) SuspendUser(ctx context.Context, actor policy.Actor, userID string) (*domain.User, error) {
+	ctx, span := startSpan(ctx, "AdminApp.SuspendUser")
+	defer span.End()
+	if err := authorize(actor, policy.UserManage, policy.Resource{}); err != nil {
+		return nil, err
+	}
+	user, err := a.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role.Name == domain.RoleAdmin {
+		return nil, ErrCannotSuspendAdmin
+	}
+	return a.setSuspended(ctx, user, true)
+}
+
+func (a *adminApp) ReactivateUser(ctx context.Context, actor policy.Actor, userID string) (*domain.User, error) {
+	ctx, span := startSpan(ctx, "AdminApp.ReactivateUser")
+	defer span.End()
+	if err := authorize(actor, policy.UserManage, policy.Resource{}); err != nil {
+		return nil, err
+	}
+	user, err := a.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return a.setSuspended(ctx, user, false)
+}
+
+func (a *adminApp) setSuspended(ctx context.Context, user *domain.User, suspended bool) (*domain.User, error) {
+	if err := a.users.SetSuspended(ctx, user.ID.String(), suspended); err != nil {
+		return nil, errors.New("Failed to update user")
+	}
+	user.Suspended = suspended
+	return user, nil
+}
+
+// TakeDownJob hides a job from every public listing. The row and its
+// applications are kept so the decision can be audited.
+func (a *adminApp) TakeDownJob(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error) {
+	ctx, span := startSpan(ctx, "AdminApp.TakeDownJob")
+	defer span.End()
+	job, err := a.jobs.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.JobModerate, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, err
+	}
+	if job.Status == domain.JobStatusRemoved {
+		return nil, ErrJobAlreadyRemoved
+	}
+	job.Status = domain.JobStatusRemoved
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.jobs.UpdateStatus(ctx, jobID, domain.JobStatusRemoved, ""); err != nil {
+			return err
+		}
+		return a.events.Publish(ctx, job.CreatedBy, domain.EventJobClosed, domain.JobClosedData{Job: job, Reason: "removed"})
+	})
+	if err != nil {
+		return nil, errors.New("Failed to take down job")
+	}
+	return job, nil
+}
+
+func (a *adminApp) Stats(ctx context.Context, actor policy.Actor) (*domain.PlatformStats, error) {
+	ctx, span := startSpan(ctx, "AdminApp.Stats")
+	defer span.End()
+	if err := authorize(actor, policy.StatsRead, policy.Resource{}); err != nil {
+		return nil, err
+	}
+	usersByRole, err := a.users.CountByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	suspended, err := a.users.CountSuspended(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobsByStatus, err := a.jobs.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appsByStatus, err := a.appRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PlatformStats{
+		UsersByRole:          usersByRole,
+		SuspendedUsers:       suspended,
+		JobsByStatus:         jobsByStatus,
+		ApplicationsByStatus: appsByStatus,
+	}, nil
+}
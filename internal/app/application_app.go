@@ -3,85 +3,249 @@ package app
 import (
 	"context"
 	"errors"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/metrics"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
 	"github.com/yesetoda/Sera_Ale/internal/repository"
 	"github.com/yesetoda/Sera_Ale/internal/service"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyApplied      = errors.New("You have already applied to this job")
+	ErrJobNotFound         = errors.New("Job not found")
+	ErrApplicationNotFound = errors.New("Application not found")
+	ErrAlreadyWithdrawn    = errors.New("Application was withdrawn")
 )
 
 type ApplicationApp interface {
-	Apply(ctx context.Context, applicantID, jobID, coverLetter string, resumeFile interface{}) (*domain.Application, []string)
-	TrackApplications(ctx context.Context, applicantID string, page, size int) ([]domain.Application, int64, error)
-	GetApplicationsForJob(ctx context.Context, jobID, companyID string, page, size int) ([]domain.Application, int64, error)
-	UpdateStatus(ctx context.Context, applicationID, companyID, status string) (*domain.Application, error)
+	Apply(ctx context.Context, actor policy.Actor, jobID, coverLetter string, resumeFile interface{}) (*domain.Application, error)
+	TrackApplications(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.Application, domain.PageInfo, error)
+	GetApplicationsForJob(ctx context.Context, actor policy.Actor, jobID string, tags []string, q domain.PageQuery) ([]domain.ReviewedApplication, domain.PageInfo, error)
+	UpdateStatus(ctx context.Context, actor policy.Actor, applicationID, status string) (*domain.Application, error)
+	Withdraw(ctx context.Context, actor policy.Actor, applicationID string) (*domain.Application, error)
 }
 
 type applicationApp struct {
 	repo    repository.ApplicationRepository
 	jobRepo repository.JobRepository
+	reviews repository.ReviewRepository
 	cloud   service.CloudinaryService
+	tx      repository.Transactor
+	events  EventPublisher
 }
 
-func NewApplicationApp(repo repository.ApplicationRepository, jobRepo repository.JobRepository, cloud service.CloudinaryService) ApplicationApp {
-	return &applicationApp{repo: repo, jobRepo: jobRepo, cloud: cloud}
+func NewApplicationApp(repo repository.ApplicationRepository, jobRepo repository.JobRepository, reviews repository.ReviewRepository, cloud service.CloudinaryService, tx repository.Transactor, events EventPublisher) ApplicationApp {
+	return &applicationApp{repo: repo, jobRepo: jobRepo, reviews: reviews, cloud: cloud, tx: tx, events: events}
 }
 
-func (a *applicationApp) Apply(ctx context.Context, applicantID, jobID, coverLetter string, resumeFile interface{}) (*domain.Application, []string) {
+// Apply uploads the resume and records the application. The unique
+// (applicant_id, job_id) constraint is the source of truth for duplicates, so
+// concurrent submissions cannot both succeed; the lookup beforehand only
+// avoids a pointless upload. The application and its event are stored in one
+// transaction; if that fails the uploaded resume is deleted again so no
+// orphaned file is left in storage.
+func (a *applicationApp) Apply(ctx context.Context, actor policy.Actor, jobID, coverLetter string, resumeFile interface{}) (*domain.Application, error) {
+	ctx, span := startSpan(ctx, "ApplicationApp.Apply")
+	defer span.End()
+	applicantID := actor.ID
+	if err := authorize(actor, policy.ApplicationCreate, policy.Resource{SubjectID: applicantID}); err != nil {
+		return nil, err
+	}
 	if len(coverLetter) > 200 {
-		return nil, []string{"Cover letter must be under 200 characters"}
+		return nil, ValidationError{"Cover letter must be under 200 characters"}
+	}
+	applicantUUID, err := uuid.Parse(applicantID)
+	if err != nil {
+		return nil, ValidationError{"Invalid applicant"}
+	}
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, ValidationError{"Invalid job ID"}
+	}
+	job, err := a.jobRepo.FindByID(ctx, jobID)
+	if err != nil || !job.Visible() {
+		return nil, ErrJobNotFound
 	}
 	if _, err := a.repo.FindByApplicantAndJob(ctx, applicantID, jobID); err == nil {
-		return nil, []string{"You have already applied to this job"}
+		return nil, ErrAlreadyApplied
 	}
 	publicID := uuid.New().String()
 	resumeURL, err := a.cloud.UploadPDF(ctx, resumeFile, publicID)
 	if err != nil {
-		return nil, []string{"Failed to upload resume"}
+		slog.ErrorContext(ctx, "resume upload failed", "error", err)
+		return nil, errors.New("Failed to upload resume")
 	}
 	app := &domain.Application{
 		ID:          uuid.New(),
-		ApplicantID: uuid.MustParse(applicantID),
-		JobID:       uuid.MustParse(jobID),
+		ApplicantID: applicantUUID,
+		JobID:       jobUUID,
 		ResumeLink:  resumeURL,
 		CoverLetter: coverLetter,
 		Status:      domain.StatusApplied,
 	}
-	if err := a.repo.Create(ctx, app); err != nil {
-		return nil, []string{"Failed to create application"}
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Create(ctx, app); err != nil {
+			return err
+		}
+		return a.events.Publish(ctx, job.CreatedBy, domain.EventApplicationCreated, domain.ApplicationEventData{Application: app, Job: job})
+	})
+	if err != nil {
+		a.discardResume(ctx, publicID)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyApplied
+		}
+		return nil, errors.New("Failed to create application")
 	}
+	metrics.ApplicationsSubmitted.Inc()
 	return app, nil
 }
 
-func (a *applicationApp) TrackApplications(ctx context.Context, applicantID string, page, size int) ([]domain.Application, int64, error) {
-	return a.repo.FindByApplicant(ctx, applicantID, page, size)
+// discardResume is the compensating action for a failed insert. It runs even
+// if the request context was cancelled.
+func (a *applicationApp) discardResume(ctx context.Context, publicID string) {
+	if err := a.cloud.DeletePDF(context.WithoutCancel(ctx), publicID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete orphaned resume", "public_id", publicID, "error", err)
+	}
 }
 
-func (a *applicationApp) GetApplicationsForJob(ctx context.Context, jobID, companyID string, page, size int) ([]domain.Application, int64, error) {
+func (a *applicationApp) TrackApplications(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.Application, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "ApplicationApp.TrackApplications")
+	defer span.End()
+	if err := authorize(actor, policy.ApplicationRead, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindByApplicant(ctx, actor.ID, q)
+}
+
+// GetApplicationsForJob lists a job's applications, optionally only those
+// carrying all of tags. The company that owns the job also gets each
+// application's tags and average rating.
+func (a *applicationApp) GetApplicationsForJob(ctx context.Context, actor policy.Actor, jobID string, tags []string, q domain.PageQuery) ([]domain.ReviewedApplication, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "ApplicationApp.GetApplicationsForJob")
+	defer span.End()
 	job, err := a.jobRepo.FindByID(ctx, jobID)
 	if err != nil {
-		return nil, 0, errors.New("Job not found")
+		return nil, domain.PageInfo{}, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.ApplicationRead, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	tags, errs := normalizeTags(tags)
+	if len(errs) > 0 {
+		return nil, domain.PageInfo{}, ValidationError(errs)
+	}
+	apps, info, err := a.repo.FindByJob(ctx, jobID, tags, q)
+	if err != nil {
+		return nil, info, err
+	}
+	reviewed := make([]domain.ReviewedApplication, 0, len(apps))
+	for _, app := range apps {
+		reviewed = append(reviewed, domain.ReviewedApplication{Application: app, Tags: []string{}})
+	}
+	if len(apps) == 0 || !policy.Can(actor, policy.ApplicationReview, policy.Resource{OwnerID: job.CreatedBy.String()}) {
+		return reviewed, info, nil
+	}
+	ids := make([]uuid.UUID, 0, len(apps))
+	for _, app := range apps {
+		ids = append(ids, app.ID)
+	}
+	appTags, err := a.reviews.FindTags(ctx, ids)
+	if err != nil {
+		return nil, info, err
+	}
+	ratings, err := a.reviews.RatingSummaries(ctx, ids)
+	if err != nil {
+		return nil, info, err
 	}
-	if job.CreatedBy.String() != companyID {
-		return nil, 0, errors.New("Unauthorized access")
+	for i := range reviewed {
+		id := reviewed[i].ID
+		if t, ok := appTags[id]; ok {
+			reviewed[i].Tags = t
+		}
+		reviewed[i].RatingSummary = ratings[id]
 	}
-	return a.repo.FindByJob(ctx, jobID, page, size)
+	return reviewed, info, nil
 }
 
-func (a *applicationApp) UpdateStatus(ctx context.Context, applicationID, companyID, status string) (*domain.Application, error) {
+func (a *applicationApp) UpdateStatus(ctx context.Context, actor policy.Actor, applicationID, status string) (*domain.Application, error) {
+	ctx, span := startSpan(ctx, "ApplicationApp.UpdateStatus")
+	defer span.End()
 	app, err := a.repo.FindByID(ctx, applicationID)
 	if err != nil {
-		return nil, errors.New("Application not found")
+		return nil, ErrApplicationNotFound
 	}
 	job, err := a.jobRepo.FindByID(ctx, app.JobID.String())
 	if err != nil {
-		return nil, errors.New("Job not found")
+		return nil, ErrJobNotFound
+	}
+	res := policy.Resource{OwnerID: job.CreatedBy.String(), SubjectID: app.ApplicantID.String()}
+	if err := authorize(actor, policy.ApplicationStatus, res); err != nil {
+		return nil, err
+	}
+	// Only the applicant can withdraw, and a withdrawn application stays so.
+	if domain.ApplicationStatus(status) == domain.StatusWithdrawn {
+		return nil, ValidationError{"Only the applicant can withdraw an application"}
 	}
-	if job.CreatedBy.String() != companyID {
-		return nil, errors.New("Unauthorized")
+	if app.Status == domain.StatusWithdrawn {
+		return nil, ErrAlreadyWithdrawn
 	}
-	if err := a.repo.UpdateStatus(ctx, applicationID, domain.ApplicationStatus(status)); err != nil {
+	var updated *domain.Application
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.UpdateStatus(ctx, applicationID, domain.ApplicationStatus(status)); err != nil {
+			return err
+		}
+		var err error
+		if updated, err = a.repo.FindByID(ctx, applicationID); err != nil {
+			return err
+		}
+		if app.Status == updated.Status {
+			return nil
+		}
+		return a.events.Publish(ctx, job.CreatedBy, domain.EventApplicationStatusChanged, domain.ApplicationEventData{Application: updated, Job: job, PreviousStatus: app.Status})
+	})
+	if err != nil {
 		return nil, errors.New("Failed to update status")
 	}
-	return a.repo.FindByID(ctx, applicationID)
+	if app.Status != updated.Status {
+		metrics.ApplicationTransitions.WithLabelValues(string(app.Status), string(updated.Status)).Inc()
+	}
+	return updated, nil
+}
+
+// Withdraw lets an applicant take back their application. The company is
+// told through the usual status change event.
+func (a *applicationApp) Withdraw(ctx context.Context, actor policy.Actor, applicationID string) (*domain.Application, error) {
+	ctx, span := startSpan(ctx, "ApplicationApp.Withdraw")
+	defer span.End()
+	app, err := a.repo.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	if err := authorize(actor, policy.ApplicationWithdraw, policy.Resource{SubjectID: app.ApplicantID.String()}); err != nil {
+		return nil, err
+	}
+	if app.Status == domain.StatusWithdrawn {
+		return nil, ErrAlreadyWithdrawn
+	}
+	job, err := a.jobRepo.FindByID(ctx, app.JobID.String())
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	previous := app.Status
+	app.Status = domain.StatusWithdrawn
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.UpdateStatus(ctx, applicationID, domain.StatusWithdrawn); err != nil {
+			return err
+		}
+		return a.events.Publish(ctx, job.CreatedBy, domain.EventApplicationStatusChanged, domain.ApplicationEventData{Application: app, Job: job, PreviousStatus: previous})
+	})
+	if err != nil {
+		return nil, errors.New("Failed to withdraw application")
+	}
+	metrics.ApplicationTransitions.WithLabelValues(string(previous), string(app.Status)).Inc()
+	return app, nil
 }
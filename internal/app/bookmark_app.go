@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+// BookmarkApp lets applicants shortlist jobs to apply to later
+type BookmarkApp interface {
+	SaveJob(ctx context.Context, actor policy.Actor, jobID string) (*domain.Bookmark, error)
+	UnsaveJob(ctx context.Context, actor policy.Actor, jobID string) error
+	SavedJobs(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.SavedJob, domain.PageInfo, error)
+}
+
+type bookmarkApp struct {
+	repo         repository.BookmarkRepository
+	jobs         repository.JobRepository
+	applications repository.ApplicationRepository
+}
+
+func NewBookmarkApp(repo repository.BookmarkRepository, jobs repository.JobRepository, applications repository.ApplicationRepository) BookmarkApp {
+	return &bookmarkApp{repo: repo, jobs: jobs, applications: applications}
+}
+
+// SaveJob bookmarks a published job. Saving a job twice is not an error.
+func (a *bookmarkApp) SaveJob(ctx context.Context, actor policy.Actor, jobID string) (*domain.Bookmark, error) {
+	ctx, span := startSpan(ctx, "BookmarkApp.SaveJob")
+	defer span.End()
+	if err := authorize(actor, policy.JobBookmark, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, err
+	}
+	applicantID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	job, err := a.jobs.FindByID(ctx, jobID)
+	if err != nil || !job.Visible() {
+		return nil, ErrJobNotFound
+	}
+	bookmark := &domain.Bookmark{ID: uuid.New(), ApplicantID: applicantID, JobID: jobUUID}
+	if err := a.repo.Create(ctx, bookmark); err != nil {
+		return nil, errors.New("Failed to save job")
+	}
+	return a.repo.FindByApplicantAndJob(ctx, actor.ID, jobID)
+}
+
+// UnsaveJob removes the bookmark; unsaving a job that is not saved is not an
+// error
+func (a *bookmarkApp) UnsaveJob(ctx context.Context, actor policy.Actor, jobID string) error {
+	ctx, span := startSpan(ctx, "BookmarkApp.UnsaveJob")
+	defer span.End()
+	if err := authorize(actor, policy.JobBookmark, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(jobID); err != nil {
+		return ErrJobNotFound
+	}
+	return a.repo.Delete(ctx, actor.ID, jobID)
+}
+
+// SavedJobs lists the actor's bookmarks with whether each job is still open
+// and whether they applied to it. Jobs and applications are loaded for the
+// whole page at once rather than per bookmark.
+func (a *bookmarkApp) SavedJobs(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.SavedJob, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "BookmarkApp.SavedJobs")
+	defer span.End()
+	if err := authorize(actor, policy.JobBookmark, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	bookmarks, info, err := a.repo.FindByApplicant(ctx, actor.ID, q)
+	if err != nil {
+		return nil, info, err
+	}
+	jobIDs := make([]uuid.UUID, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		jobIDs = append(jobIDs, b.JobID)
+	}
+	jobs, err := a.jobs.FindByIDs(ctx, jobIDs)
+	if err != nil {
+		return nil, info, err
+	}
+	apps, err := a.applications.FindByApplicantAndJobs(ctx, actor.ID, jobIDs)
+	if err != nil {
+		return nil, info, err
+	}
+	jobsByID := make(map[uuid.UUID]*domain.Job, len(jobs))
+	for i := range jobs {
+		// Moderation notes are for the company and admins only.
+		jobs[i].HoldReason = ""
+		jobsByID[jobs[i].ID] = &jobs[i]
+	}
+	appsByJob := make(map[uuid.UUID]uuid.UUID, len(apps))
+	for _, app := range apps {
+		appsByJob[app.JobID] = app.ID
+	}
+	saved := make([]domain.SavedJob, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		job, ok := jobsByID[b.JobID]
+		if !ok {
+			continue
+		}
+		s := domain.SavedJob{Job: job, SavedAt: b.CreatedAt, Open: job.Visible()}
+		if appID, ok := appsByJob[b.JobID]; ok {
+			s.Applied = true
+			s.ApplicationID = &appID
+		}
+		saved = append(saved, s)
+	}
+	return saved, info, nil
+}
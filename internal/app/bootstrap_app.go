@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"github.com/yesetoda/Sera_Ale/internal/service"
+)
+
+// SeedRoles are the roles every deployment needs
+var SeedRoles = []string{domain.RoleApplicant, domain.RoleCompany, domain.RoleAdmin}
+
+// AdminSeed describes the optional initial admin account. It is only created
+// when Email is set.
+type AdminSeed struct {
+	Name     string
+	Email    string
+	Password string
+}
+
+// SeedStatus reports which seed roles are present in the database
+type SeedStatus struct {
+	Roles    map[string]bool `json:"roles"`
+	Complete bool            `json:"complete"`
+}
+
+type BootstrapApp interface {
+	Seed(ctx context.Context) error
+	Status(ctx context.Context) (*SeedStatus, error)
+}
+
+type bootstrapApp struct {
+	roles    repository.RoleRepository
+	users    repository.UserRepository
+	password service.PasswordService
+	admin    AdminSeed
+}
+
+func NewBootstrapApp(roles repository.RoleRepository, users repository.UserRepository, password service.PasswordService, admin AdminSeed) BootstrapApp {
+	return &bootstrapApp{roles: roles, users: users, password: password, admin: admin}
+}
+
+// Seed inserts the missing roles and creates the initial admin if one is
+// configured. Running it again is a no-op.
+func (a *bootstrapApp) Seed(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "BootstrapApp.Seed")
+	defer span.End()
+	if err := a.roles.Ensure(ctx, SeedRoles); err != nil {
+		return fmt.Errorf("seed roles: %w", err)
+	}
+	if a.admin.Email == "" {
+		return nil
+	}
+	if existing, err := a.users.FindByEmail(ctx, a.admin.Email); err == nil {
+		if existing.Role.Name != domain.RoleAdmin {
+			slog.WarnContext(ctx, "bootstrap: admin email already registered with another role; not promoting it", "email", a.admin.Email, "role", existing.Role.Name)
+		}
+		return nil
+	}
+	if errs := validateCredentials(a.admin.Name, a.admin.Email, a.admin.Password); len(errs) > 0 {
+		return fmt.Errorf("initial admin: %w", ValidationError(errs))
+	}
+	role, err := a.roles.FindByName(ctx, domain.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("initial admin: %w", err)
+	}
+	hash, err := a.password.HashPassword(a.admin.Password)
+	if err != nil {
+		return fmt.Errorf("initial admin: %w", err)
+	}
+	user := &domain.User{
+		ID:       uuid.New(),
+		Name:     a.admin.Name,
+		Email:    a.admin.Email,
+		Password: hash,
+		RoleID:   role.ID,
+	}
+	if err := a.users.Create(ctx, user); err != nil {
+		return fmt.Errorf("initial admin: %w", err)
+	}
+	slog.InfoContext(ctx, "bootstrap: created initial admin", "email", a.admin.Email)
+	return nil
+}
+
+func (a *bootstrapApp) Status(ctx context.Context) (*SeedStatus, error) {
+	ctx, span := startSpan(ctx, "BootstrapApp.Status")
+	defer span.End()
+	names, err := a.roles.ListNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &SeedStatus{Roles: map[string]bool{}, Complete: true}
+	for _, role := range SeedRoles {
+		status.Roles[role] = false
+	}
+	for _, name := range names {
+		if _, ok := status.Roles[name]; ok {
+			status.Roles[name] = true
+		}
+	}
+	for _, present := range status.Roles {
+		if !present {
+			status.Complete = false
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,10 @@
+package app
+
+import "strings"
+
+// ValidationError lists user-facing reasons why input was rejected
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return strings.Join(e, "; ")
+}
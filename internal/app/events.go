@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+// EventPublisher receives domain events about a company's jobs and their
+// applications. Callers publish inside the transaction that makes the
+// change, so the event is stored if and only if the change is; an error
+// rolls both back.
+type EventPublisher interface {
+	Publish(ctx context.Context, companyID uuid.UUID, event domain.WebhookEvent, data interface{}) error
+}
+
+// Publishers fans every event out to each of ps in order, stopping at the
+// first error
+func Publishers(ps ...EventPublisher) EventPublisher {
+	return publishers(ps)
+}
+
+type publishers []EventPublisher
+
+func (ps publishers) Publish(ctx context.Context, companyID uuid.UUID, event domain.WebhookEvent, data interface{}) error {
+	for _, p := range ps {
+		if err := p.Publish(ctx, companyID, event, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
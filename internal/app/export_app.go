@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"github.com/yesetoda/Sera_Ale/internal/service"
+)
+
+const exportBatchSize = 500
+
+var ErrInvalidLink = errors.New("Link is invalid or has expired")
+
+// applicantColumns is the header row of an applicant export
+var applicantColumns = []string{"Application ID", "Name", "Email", "Status", "Applied At", "Cover Letter", "Resume"}
+
+// ApplicantExport is a job's applicants as a table. Rows reads the
+// applications a batch at a time while it is iterated, so it can only be
+// iterated once and only while the context passed to ExportApplicants is
+// alive.
+type ApplicantExport struct {
+	Job    *domain.Job
+	Header []string
+	Rows   iter.Seq2[[]string, error]
+}
+
+// ExportApp exports a job's applicants for spreadsheets. Resume links in an
+// export are signed and expire, as a spreadsheet cannot send a Bearer token.
+type ExportApp interface {
+	ExportApplicants(ctx context.Context, actor policy.Actor, jobID string) (*ApplicantExport, error)
+	ResumeURL(ctx context.Context, applicationID string, expires int64, signature string) (string, error)
+}
+
+type exportApp struct {
+	applications repository.ApplicationRepository
+	jobs         repository.JobRepository
+	signer       service.LinkSigner
+	publicURL    string
+	linkTTL      time.Duration
+}
+
+// NewExportApp builds the export app. Resume links point at publicURL and
+// stay valid for linkTTL.
+func NewExportApp(applications repository.ApplicationRepository, jobs repository.JobRepository, signer service.LinkSigner, publicURL string, linkTTL time.Duration) ExportApp {
+	return &exportApp{applications: applications, jobs: jobs, signer: signer, publicURL: publicURL, linkTTL: linkTTL}
+}
+
+// ExportApplicants checks that the actor may read the job's applications
+// and returns every one of them, oldest first
+func (a *exportApp) ExportApplicants(ctx context.Context, actor policy.Actor, jobID string) (*ApplicantExport, error) {
+	ctx, span := startSpan(ctx, "ExportApp.ExportApplicants")
+	defer span.End()
+	job, err := a.jobs.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.ApplicationRead, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(a.linkTTL)
+	rows := func(yield func([]string, error) bool) {
+		var after *domain.Cursor
+		for {
+			batch, err := a.applications.FindApplicantRows(ctx, job.ID.String(), after, exportBatchSize)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, row := range batch {
+				cells := []string{
+					row.ApplicationID.String(),
+					row.Name,
+					row.Email,
+					string(row.Status),
+					row.AppliedAt.UTC().Format(time.RFC3339),
+					row.CoverLetter,
+					a.resumeLink(row, expires),
+				}
+				if !yield(cells, nil) {
+					return
+				}
+			}
+			if len(batch) < exportBatchSize {
+				return
+			}
+			last := batch[len(batch)-1]
+			after = &domain.Cursor{CreatedAt: last.AppliedAt, ID: last.ApplicationID}
+		}
+	}
+	return &ApplicantExport{Job: job, Header: applicantColumns, Rows: rows}, nil
+}
+
+func (a *exportApp) resumeLink(row domain.ApplicantRow, expires time.Time) string {
+	if row.ResumeLink == "" {
+		return ""
+	}
+	id := row.ApplicationID.String()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	q.Set("signature", a.signer.Sign(resumeResource(id), expires))
+	return a.publicURL + "/resumes/" + id + "?" + q.Encode()
+}
+
+// ResumeURL checks a signed resume link and returns where the resume is
+// stored
+func (a *exportApp) ResumeURL(ctx context.Context, applicationID string, expires int64, signature string) (string, error) {
+	ctx, span := startSpan(ctx, "ExportApp.ResumeURL")
+	defer span.End()
+	if !a.signer.Verify(resumeResource(applicationID), expires, signature) {
+		return "", ErrInvalidLink
+	}
+	application, err := a.applications.FindByID(ctx, applicationID)
+	if err != nil || application.ResumeLink == "" {
+		return "", ErrApplicationNotFound
+	}
+	return application.ResumeLink, nil
+}
+
+func resumeResource(applicationID string) string {
+	return "resume:" + applicationID
+}
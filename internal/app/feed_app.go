@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/feed"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+// feedSize is how many of the newest jobs a feed lists
+const feedSize = 50
+
+// feedSource names the site in feeds and as the JobPosting identifier
+const feedSource = "Sera Ale"
+
+var (
+	ErrCompanyNotFound = errors.New("Company not found")
+	ErrNoJobPosting    = errors.New("Job has no location search engines accept")
+)
+
+// FeedApp publishes published jobs for aggregators and search engines: as
+// feeds of the newest jobs, overall or per company, and as schema.org
+// JobPosting data for a single job.
+type FeedApp interface {
+	JobsFeed(ctx context.Context) (*feed.Feed, error)
+	CompanyFeed(ctx context.Context, companyID string) (*feed.Feed, error)
+	JobPosting(ctx context.Context, actor policy.Actor, jobID string) (*feed.JobPosting, error)
+}
+
+type feedApp struct {
+	jobs          JobApp
+	users         repository.UserRepository
+	publicURL     string
+	remoteCountry string
+}
+
+// NewFeedApp builds the feed app. Links in feeds point at publicURL, and
+// remote jobs are published as open to applicants in remoteCountry.
+func NewFeedApp(jobs JobApp, users repository.UserRepository, publicURL, remoteCountry string) FeedApp {
+	return &feedApp{jobs: jobs, users: users, publicURL: publicURL, remoteCountry: remoteCountry}
+}
+
+// JobsFeed lists the newest published jobs of every company
+func (a *feedApp) JobsFeed(ctx context.Context) (*feed.Feed, error) {
+	ctx, span := startSpan(ctx, "FeedApp.JobsFeed")
+	defer span.End()
+	jobs, _, err := a.jobs.SearchJobs(ctx, map[string]interface{}{}, domain.PageQuery{Page: 1, Size: feedSize})
+	if err != nil {
+		return nil, err
+	}
+	f := &feed.Feed{
+		Title:       feedSource + " jobs",
+		Description: "The newest jobs posted on " + feedSource,
+		Link:        a.publicURL + "/jobs",
+		Self:        a.publicURL + "/jobs/feed",
+	}
+	return f, a.addItems(ctx, f, jobs)
+}
+
+// CompanyFeed lists the newest published jobs of one company
+func (a *feedApp) CompanyFeed(ctx context.Context, companyID string) (*feed.Feed, error) {
+	ctx, span := startSpan(ctx, "FeedApp.CompanyFeed")
+	defer span.End()
+	if _, err := uuid.Parse(companyID); err != nil {
+		return nil, ErrCompanyNotFound
+	}
+	company, err := a.users.FindByID(ctx, companyID)
+	if err != nil || company.Role.Name != domain.RoleCompany {
+		return nil, ErrCompanyNotFound
+	}
+	jobs, _, err := a.jobs.GetJobsByCompany(ctx, companyID, domain.PageQuery{Page: 1, Size: feedSize})
+	if err != nil {
+		return nil, err
+	}
+	f := &feed.Feed{
+		Title:       company.Name + " jobs on " + feedSource,
+		Description: "The newest jobs posted by " + company.Name,
+		Link:        a.publicURL + "/jobs?company_id=" + companyID,
+		Self:        a.publicURL + "/companies/" + companyID + "/feed",
+	}
+	return f, a.addItems(ctx, f, jobs)
+}
+
+func (a *feedApp) addItems(ctx context.Context, f *feed.Feed, jobs []domain.Job) error {
+	names, err := a.companyNames(ctx, jobs)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		f.Items = append(f.Items, feed.Item{
+			ID:        "urn:uuid:" + job.ID.String(),
+			Title:     job.Title,
+			Link:      a.jobURL(job.ID),
+			Summary:   job.Description,
+			Author:    names[job.CreatedBy],
+			Published: job.CreatedAt,
+		})
+	}
+	return nil
+}
+
+// JobPosting returns a job as schema.org JobPosting data, to whoever may
+// see the job. Jobs search engines would reject as invalid return
+// ErrNoJobPosting instead.
+func (a *feedApp) JobPosting(ctx context.Context, actor policy.Actor, jobID string) (*feed.JobPosting, error) {
+	ctx, span := startSpan(ctx, "FeedApp.JobPosting")
+	defer span.End()
+	job, err := a.jobs.GetJobByID(ctx, actor, jobID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := a.companyNames(ctx, []domain.Job{*job})
+	if err != nil {
+		return nil, err
+	}
+	posting, ok := feed.NewJobPosting(job.ID.String(), job.Title, job.Description, job.Location, a.remoteCountry, a.jobURL(job.ID), job.CreatedAt, feedSource, names[job.CreatedBy])
+	if !ok {
+		return nil, ErrNoJobPosting
+	}
+	return &posting, nil
+}
+
+func (a *feedApp) jobURL(id uuid.UUID) string {
+	return a.publicURL + "/jobs/" + id.String()
+}
+
+// companyNames looks up the names of the companies that posted jobs
+func (a *feedApp) companyNames(ctx context.Context, jobs []domain.Job) (map[uuid.UUID]string, error) {
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.CreatedBy)
+	}
+	users, err := a.users.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,448 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/ical"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+const (
+	maxInterviewSlots    = 5
+	maxInterviewLength   = 8 * time.Hour
+	maxInterviewers      = 10
+	reminderBatchSize    = 50
+	interviewTimeDisplay = "Mon 2 Jan 2006 15:04 MST"
+)
+
+var (
+	ErrInterviewNotFound     = errors.New("Interview not found")
+	ErrInterviewCancelled    = errors.New("Interview is cancelled")
+	ErrSlotAlreadyChosen     = errors.New("A slot was already chosen; ask the company to reschedule")
+	ErrInterviewNotScheduled = errors.New("Interview has no confirmed time yet")
+)
+
+// InterviewPlan is what a company proposes: the slots to choose from and
+// where and with whom the interview happens
+type InterviewPlan struct {
+	Slots        []domain.InterviewSlot
+	Location     string
+	MeetingURL   string
+	Interviewers []string
+	Notes        string
+}
+
+// InterviewApp schedules interviews for applications. Every change is
+// notified to the other side, and both sides are reminded ahead of time.
+type InterviewApp interface {
+	ProposeInterview(ctx context.Context, actor policy.Actor, applicationID string, plan InterviewPlan) (*domain.Interview, error)
+	Reschedule(ctx context.Context, actor policy.Actor, interviewID string, plan InterviewPlan) (*domain.Interview, error)
+	ChooseSlot(ctx context.Context, actor policy.Actor, interviewID string, slot int) (*domain.Interview, error)
+	Cancel(ctx context.Context, actor policy.Actor, interviewID, reason string) (*domain.Interview, error)
+	GetInterview(ctx context.Context, actor policy.Actor, interviewID string) (*domain.Interview, error)
+	ApplicationInterviews(ctx context.Context, actor policy.Actor, applicationID string) ([]domain.Interview, error)
+	ApplicantInterviews(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.Interview, domain.PageInfo, error)
+	Calendar(ctx context.Context, actor policy.Actor, interviewID string) ([]byte, error)
+	SendReminders(ctx context.Context)
+}
+
+type interviewApp struct {
+	repo          repository.InterviewRepository
+	applications  repository.ApplicationRepository
+	jobs          repository.JobRepository
+	tx            repository.Transactor
+	notifications NotificationApp
+	reminderLead  time.Duration
+}
+
+// NewInterviewApp builds the interview app. Reminders go out reminderLead
+// before an interview starts.
+func NewInterviewApp(repo repository.InterviewRepository, applications repository.ApplicationRepository, jobs repository.JobRepository, tx repository.Transactor, notifications NotificationApp, reminderLead time.Duration) InterviewApp {
+	return &interviewApp{repo: repo, applications: applications, jobs: jobs, tx: tx, notifications: notifications, reminderLead: reminderLead}
+}
+
+// ProposeInterview offers interview slots on an application. Withdrawn
+// applications and jobs taken off the site are refused, as for messages.
+func (a *interviewApp) ProposeInterview(ctx context.Context, actor policy.Actor, applicationID string, plan InterviewPlan) (*domain.Interview, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.ProposeInterview")
+	defer span.End()
+	application, err := a.applications.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	job, err := a.jobs.FindByID(ctx, application.JobID.String())
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.InterviewManage, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, err
+	}
+	if job.Status == domain.JobStatusRemoved {
+		return nil, ErrJobNotFound
+	}
+	if application.Status == domain.StatusWithdrawn {
+		return nil, ErrAlreadyWithdrawn
+	}
+	if errs := validatePlan(&plan); len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	interview := &domain.Interview{
+		ID:            uuid.New(),
+		ApplicationID: application.ID,
+		JobID:         job.ID,
+		CompanyID:     job.CreatedBy,
+		ApplicantID:   application.ApplicantID,
+		Status:        domain.InterviewProposed,
+	}
+	applyPlan(interview, plan)
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Create(ctx, interview); err != nil {
+			return err
+		}
+		return a.notify(ctx, interview.ApplicantID, interview, fmt.Sprintf("Interview invitation for %s", job.Title),
+			fmt.Sprintf("You are invited to interview for %s. Pick one of the proposed times.", job.Title))
+	})
+	if err != nil {
+		return nil, errors.New("Failed to create interview")
+	}
+	return interview, nil
+}
+
+// Reschedule replaces the slots and details of an interview. The applicant
+// has to pick a slot again, even if the interview was already booked.
+func (a *interviewApp) Reschedule(ctx context.Context, actor policy.Actor, interviewID string, plan InterviewPlan) (*domain.Interview, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.Reschedule")
+	defer span.End()
+	interview, err := a.find(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(actor, policy.InterviewManage, policy.Resource{OwnerID: interview.CompanyID.String()}); err != nil {
+		return nil, err
+	}
+	if interview.Status == domain.InterviewCancelled {
+		return nil, ErrInterviewCancelled
+	}
+	if errs := validatePlan(&plan); len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	applyPlan(interview, plan)
+	interview.Status = domain.InterviewProposed
+	interview.StartsAt, interview.EndsAt = nil, nil
+	interview.ReminderSentAt = nil
+	interview.Sequence++
+	title := a.jobTitle(ctx, interview)
+	err = a.update(ctx, interview, interview.ApplicantID, fmt.Sprintf("Interview for %s rescheduled", title),
+		fmt.Sprintf("Your interview for %s was rescheduled. Pick one of the new times.", title))
+	if err != nil {
+		return nil, err
+	}
+	return interview, nil
+}
+
+// ChooseSlot books the slot at index slot of the proposed ones
+func (a *interviewApp) ChooseSlot(ctx context.Context, actor policy.Actor, interviewID string, slot int) (*domain.Interview, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.ChooseSlot")
+	defer span.End()
+	interview, err := a.find(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(actor, policy.InterviewRespond, policy.Resource{SubjectID: interview.ApplicantID.String()}); err != nil {
+		return nil, err
+	}
+	switch interview.Status {
+	case domain.InterviewCancelled:
+		return nil, ErrInterviewCancelled
+	case domain.InterviewScheduled:
+		return nil, ErrSlotAlreadyChosen
+	}
+	if slot < 0 || slot >= len(interview.Slots) {
+		return nil, ValidationError{fmt.Sprintf("Slot must be between 0 and %d", len(interview.Slots)-1)}
+	}
+	chosen := interview.Slots[slot]
+	if !chosen.StartsAt.After(time.Now()) {
+		return nil, ValidationError{"That slot has already passed"}
+	}
+	interview.Status = domain.InterviewScheduled
+	interview.StartsAt, interview.EndsAt = &chosen.StartsAt, &chosen.EndsAt
+	interview.Sequence++
+	title := a.jobTitle(ctx, interview)
+	err = a.update(ctx, interview, interview.CompanyID, fmt.Sprintf("Interview booked for %s", title),
+		fmt.Sprintf("The applicant chose %s for their interview for %s.", chosen.StartsAt.Format(interviewTimeDisplay), title))
+	if err != nil {
+		return nil, err
+	}
+	return interview, nil
+}
+
+// Cancel can be done by the company or the applicant; the other side is
+// notified
+func (a *interviewApp) Cancel(ctx context.Context, actor policy.Actor, interviewID, reason string) (*domain.Interview, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.Cancel")
+	defer span.End()
+	interview, err := a.find(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	byCompany := policy.Can(actor, policy.InterviewManage, policy.Resource{OwnerID: interview.CompanyID.String()})
+	if !byCompany && !policy.Can(actor, policy.InterviewRespond, policy.Resource{SubjectID: interview.ApplicantID.String()}) {
+		return nil, ErrUnauthorized
+	}
+	if interview.Status == domain.InterviewCancelled {
+		return nil, ErrInterviewCancelled
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > 500 {
+		return nil, ValidationError{"Reason must be at most 500 characters"}
+	}
+	interview.Status = domain.InterviewCancelled
+	interview.CancelReason = reason
+	interview.Sequence++
+	title := a.jobTitle(ctx, interview)
+	recipient, by := interview.CompanyID, "The applicant"
+	if byCompany {
+		recipient, by = interview.ApplicantID, "The company"
+	}
+	body := fmt.Sprintf("%s cancelled the interview for %s.", by, title)
+	if reason != "" {
+		body += " Reason: " + reason
+	}
+	if err := a.update(ctx, interview, recipient, fmt.Sprintf("Interview for %s cancelled", title), body); err != nil {
+		return nil, err
+	}
+	return interview, nil
+}
+
+func (a *interviewApp) GetInterview(ctx context.Context, actor policy.Actor, interviewID string) (*domain.Interview, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.GetInterview")
+	defer span.End()
+	interview, err := a.find(ctx, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(actor, policy.InterviewRead, interviewResource(interview)); err != nil {
+		return nil, err
+	}
+	return interview, nil
+}
+
+func (a *interviewApp) ApplicationInterviews(ctx context.Context, actor policy.Actor, applicationID string) ([]domain.Interview, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.ApplicationInterviews")
+	defer span.End()
+	application, err := a.applications.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	job, err := a.jobs.FindByID(ctx, application.JobID.String())
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	res := policy.Resource{OwnerID: job.CreatedBy.String(), SubjectID: application.ApplicantID.String()}
+	if err := authorize(actor, policy.InterviewRead, res); err != nil {
+		return nil, err
+	}
+	return a.repo.FindByApplication(ctx, applicationID)
+}
+
+func (a *interviewApp) ApplicantInterviews(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.Interview, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.ApplicantInterviews")
+	defer span.End()
+	if err := authorize(actor, policy.InterviewRespond, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindByApplicant(ctx, actor.ID, q)
+}
+
+// Calendar renders a booked or cancelled interview as an .ics file.
+// Cancelled interviews produce a cancellation that removes the event from
+// calendars it was imported into.
+func (a *interviewApp) Calendar(ctx context.Context, actor policy.Actor, interviewID string) ([]byte, error) {
+	ctx, span := startSpan(ctx, "InterviewApp.Calendar")
+	defer span.End()
+	interview, err := a.GetInterview(ctx, actor, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	if interview.StartsAt == nil || interview.EndsAt == nil {
+		return nil, ErrInterviewNotScheduled
+	}
+	title := a.jobTitle(ctx, interview)
+	description := interview.Notes
+	if len(interview.Interviewers) > 0 {
+		description = strings.TrimSpace("Interviewers: " + strings.Join(interview.Interviewers, ", ") + "\n\n" + description)
+	}
+	location := interview.Location
+	if location == "" {
+		location = interview.MeetingURL
+	}
+	return ical.Encode(ical.Event{
+		UID:         interview.ID.String() + "@sera-ale",
+		Sequence:    interview.Sequence,
+		Stamp:       interview.UpdatedAt,
+		Start:       *interview.StartsAt,
+		End:         *interview.EndsAt,
+		Summary:     "Interview: " + title,
+		Description: description,
+		Location:    location,
+		URL:         interview.MeetingURL,
+		Cancelled:   interview.Status == domain.InterviewCancelled,
+	}), nil
+}
+
+// SendReminders notifies both sides of every booked interview starting
+// within the reminder lead time. It is meant to be run periodically from a
+// worker.
+func (a *interviewApp) SendReminders(ctx context.Context) {
+	ctx, span := startSpan(ctx, "InterviewApp.SendReminders")
+	defer span.End()
+	for ctx.Err() == nil {
+		due, err := a.repo.ClaimDueReminders(ctx, time.Now(), a.reminderLead, reminderBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "interview: failed to claim reminders", "error", err)
+			}
+			return
+		}
+		for i := range due {
+			interview := &due[i]
+			title := a.jobTitle(ctx, interview)
+			subject := fmt.Sprintf("Reminder: interview for %s", title)
+			body := fmt.Sprintf("The interview for %s starts at %s.", title, interview.StartsAt.Format(interviewTimeDisplay))
+			if where := interviewPlace(interview); where != "" {
+				body += " " + where
+			}
+			data := interviewRefs(interview)
+			a.notifications.Notify(ctx, interview.ApplicantID, domain.NotifyInterviewReminder, subject, body, data)
+			a.notifications.Notify(ctx, interview.CompanyID, domain.NotifyInterviewReminder, subject, body, data)
+		}
+		if len(due) < reminderBatchSize {
+			return
+		}
+	}
+}
+
+func (a *interviewApp) find(ctx context.Context, interviewID string) (*domain.Interview, error) {
+	interview, err := a.repo.FindByID(ctx, interviewID)
+	if err != nil {
+		return nil, ErrInterviewNotFound
+	}
+	return interview, nil
+}
+
+// jobTitle names the interview's job in messages; a missing job only makes
+// the message vaguer
+func (a *interviewApp) jobTitle(ctx context.Context, interview *domain.Interview) string {
+	job, err := a.jobs.FindByID(ctx, interview.JobID.String())
+	if err != nil {
+		return "your application"
+	}
+	return job.Title
+}
+
+// update saves a change to interview and notifies userID about it in one
+// transaction
+func (a *interviewApp) update(ctx context.Context, interview *domain.Interview, userID uuid.UUID, title, body string) error {
+	err := a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Update(ctx, interview); err != nil {
+			return err
+		}
+		return a.notify(ctx, userID, interview, title, body)
+	})
+	if err != nil {
+		return errors.New("Failed to update interview")
+	}
+	return nil
+}
+
+func (a *interviewApp) notify(ctx context.Context, userID uuid.UUID, interview *domain.Interview, title, body string) error {
+	return a.notifications.Notify(ctx, userID, domain.NotifyInterview, title, body, interviewRefs(interview))
+}
+
+func interviewRefs(interview *domain.Interview) map[string]string {
+	return map[string]string{
+		"interview_id":   interview.ID.String(),
+		"application_id": interview.ApplicationID.String(),
+		"job_id":         interview.JobID.String(),
+	}
+}
+
+func interviewResource(interview *domain.Interview) policy.Resource {
+	return policy.Resource{OwnerID: interview.CompanyID.String(), SubjectID: interview.ApplicantID.String()}
+}
+
+func interviewPlace(interview *domain.Interview) string {
+	switch {
+	case interview.Location != "" && interview.MeetingURL != "":
+		return fmt.Sprintf("Location: %s. Join at %s", interview.Location, interview.MeetingURL)
+	case interview.Location != "":
+		return "Location: " + interview.Location
+	case interview.MeetingURL != "":
+		return "Join at " + interview.MeetingURL
+	}
+	return ""
+}
+
+// validatePlan trims plan and lists what is wrong with it
+func validatePlan(plan *InterviewPlan) []string {
+	var errs []string
+	if len(plan.Slots) == 0 || len(plan.Slots) > maxInterviewSlots {
+		errs = append(errs, fmt.Sprintf("Propose between 1 and %d slots", maxInterviewSlots))
+	}
+	now := time.Now()
+	for i, slot := range plan.Slots {
+		switch {
+		case !slot.StartsAt.After(now):
+			errs = append(errs, fmt.Sprintf("Slot %d must start in the future", i))
+		case !slot.EndsAt.After(slot.StartsAt):
+			errs = append(errs, fmt.Sprintf("Slot %d must end after it starts", i))
+		case slot.EndsAt.Sub(slot.StartsAt) > maxInterviewLength:
+			errs = append(errs, fmt.Sprintf("Slot %d must be at most %s long", i, maxInterviewLength))
+		}
+		plan.Slots[i] = domain.InterviewSlot{StartsAt: slot.StartsAt.UTC(), EndsAt: slot.EndsAt.UTC()}
+	}
+	plan.Location = strings.TrimSpace(plan.Location)
+	plan.MeetingURL = strings.TrimSpace(plan.MeetingURL)
+	if plan.Location == "" && plan.MeetingURL == "" {
+		errs = append(errs, "Give a location or a meeting link")
+	}
+	if len(plan.Location) > 200 {
+		errs = append(errs, "Location must be at most 200 characters")
+	}
+	if plan.MeetingURL != "" {
+		if u, err := url.Parse(plan.MeetingURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, "Meeting link must be an absolute http(s) URL")
+		}
+	}
+	interviewers := []string{}
+	for _, name := range plan.Interviewers {
+		if name = strings.TrimSpace(name); name != "" {
+			interviewers = append(interviewers, name)
+		}
+	}
+	plan.Interviewers = interviewers
+	if len(interviewers) > maxInterviewers {
+		errs = append(errs, fmt.Sprintf("List at most %d interviewers", maxInterviewers))
+	}
+	if len(plan.Notes) > 2000 {
+		errs = append(errs, "Notes must be at most 2000 characters")
+	}
+	return errs
+}
+
+func applyPlan(interview *domain.Interview, plan InterviewPlan) {
+	interview.Slots = plan.Slots
+	interview.Location = plan.Location
+	interview.MeetingURL = plan.MeetingURL
+	interview.Interviewers = plan.Interviewers
+	interview.Notes = plan.Notes
+}
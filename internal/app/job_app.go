@@ -3,57 +3,176 @@ package app
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/metrics"
+	"github.com/yesetoda/Sera_Ale/internal/moderation"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
 	"github.com/yesetoda/Sera_Ale/internal/repository"
 )
 
 var ErrUnauthorized = errors.New("Unauthorized access")
 
 type JobApp interface {
-	CreateJob(ctx context.Context, job *domain.Job) error
-	UpdateJob(ctx context.Context, job *domain.Job) error
-	DeleteJob(ctx context.Context, jobID string, userID string) error
-	GetJobByID(ctx context.Context, jobID string) (*domain.Job, error)
-	GetJobsByCompany(ctx context.Context, companyID string, page, size int) ([]domain.Job, int64, error)
-	SearchJobs(ctx context.Context, filters map[string]interface{}, page, size int) ([]domain.Job, int64, error)
+	CreateJob(ctx context.Context, actor policy.Actor, job *domain.Job) error
+	UpdateJob(ctx context.Context, actor policy.Actor, changes *domain.Job) (*domain.Job, error)
+	DeleteJob(ctx context.Context, actor policy.Actor, jobID string) error
+	GetJobByID(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error)
+	GetJobsByCompany(ctx context.Context, companyID string, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error)
+	SearchJobs(ctx context.Context, filters map[string]interface{}, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error)
+	ImportJobs(ctx context.Context, actor policy.Actor, inputs []JobInput, dryRun bool) (*domain.JobImportResult, error)
 }
 
 type jobApp struct {
-	repo repository.JobRepository
+	repo   repository.JobRepository
+	users  repository.UserRepository
+	rules  moderation.Rules
+	tx     repository.Transactor
+	events EventPublisher
 }
 
-func NewJobApp(repo repository.JobRepository) JobApp {
-	return &jobApp{repo: repo}
+func NewJobApp(repo repository.JobRepository, users repository.UserRepository, rules moderation.Rules, tx repository.Transactor, events EventPublisher) JobApp {
+	return &jobApp{repo: repo, users: users, rules: rules, tx: tx, events: events}
 }
 
-func (a *jobApp) CreateJob(ctx context.Context, job *domain.Job) error {
-	return a.repo.Create(ctx, job)
+// authorize returns ErrUnauthorized unless the policy grants perm on res
+func authorize(actor policy.Actor, perm policy.Permission, res policy.Resource) error {
+	if !policy.Can(actor, perm, res) {
+		return ErrUnauthorized
+	}
+	return nil
 }
 
-func (a *jobApp) UpdateJob(ctx context.Context, job *domain.Job) error {
-	return a.repo.Update(ctx, job)
+func (a *jobApp) CreateJob(ctx context.Context, actor policy.Actor, job *domain.Job) error {
+	ctx, span := startSpan(ctx, "JobApp.CreateJob")
+	defer span.End()
+	if err := authorize(actor, policy.JobCreate, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return err
+	}
+	job.Status = domain.JobStatusPublished
+	if err := a.screen(ctx, job, true); err != nil {
+		return err
+	}
+	if err := a.repo.Create(ctx, job); err != nil {
+		return err
+	}
+	metrics.JobsCreated.WithLabelValues(string(job.Status)).Inc()
+	return nil
 }
 
-func (a *jobApp) DeleteJob(ctx context.Context, jobID string, userID string) error {
-	job, err := a.repo.FindByID(ctx, jobID)
+// screen runs the auto-hold rules and moves job to held if any of them
+// fire. The new-account rule only applies to jobs being created.
+func (a *jobApp) screen(ctx context.Context, job *domain.Job, isNew bool) error {
+	var sub moderation.Submission
+	if isNew {
+		var err error
+		if sub, err = a.history(ctx, job.CreatedBy.String()); err != nil {
+			return err
+		}
+	}
+	a.hold(job, sub)
+	return nil
+}
+
+// history fills in the account age and recent job count the new-account
+// rule looks at, when that rule is enabled
+func (a *jobApp) history(ctx context.Context, companyID string) (moderation.Submission, error) {
+	var sub moderation.Submission
+	if a.rules.NewAccountAge <= 0 {
+		return sub, nil
+	}
+	author, err := a.users.FindByID(ctx, companyID)
 	if err != nil {
-		return err
+		return sub, err
 	}
-	if job.CreatedBy.String() != userID {
-		return ErrUnauthorized
+	sub.AccountAge = time.Since(author.CreatedAt)
+	sub.RecentJobs, err = a.repo.CountByCompanySince(ctx, companyID, time.Now().Add(-a.rules.NewAccountAge))
+	return sub, err
+}
+
+// hold checks job against the rules with the account history in sub
+func (a *jobApp) hold(job *domain.Job, sub moderation.Submission) {
+	sub.Title, sub.Description = job.Title, job.Description
+	if reasons := a.rules.Check(sub); len(reasons) > 0 {
+		job.Status = domain.JobStatusHeld
+		job.HoldReason = strings.Join(reasons, "; ")
 	}
-	return a.repo.Delete(ctx, jobID)
 }
 
-func (a *jobApp) GetJobByID(ctx context.Context, jobID string) (*domain.Job, error) {
-	return a.repo.FindByID(ctx, jobID)
+// UpdateJob copies the editable fields of changes onto the stored job
+// identified by changes.ID
+func (a *jobApp) UpdateJob(ctx context.Context, actor policy.Actor, changes *domain.Job) (*domain.Job, error) {
+	ctx, span := startSpan(ctx, "JobApp.UpdateJob")
+	defer span.End()
+	job, err := a.repo.FindByID(ctx, changes.ID.String())
+	if err != nil || job.Status == domain.JobStatusRemoved {
+		return nil, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.JobUpdate, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, err
+	}
+	job.Title = changes.Title
+	job.Description = changes.Description
+	job.Location = changes.Location
+	if job.Status == domain.JobStatusPublished {
+		if err := a.screen(ctx, job, false); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.repo.Update(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// DeleteJob takes a job off the site by marking it removed. The row is kept
+// so its applications, threads and interviews stay consistent; they are
+// hidden the same way as for a job an admin took down.
+func (a *jobApp) DeleteJob(ctx context.Context, actor policy.Actor, jobID string) error {
+	ctx, span := startSpan(ctx, "JobApp.DeleteJob")
+	defer span.End()
+	job, err := a.repo.FindByID(ctx, jobID)
+	if err != nil || job.Status == domain.JobStatusRemoved {
+		return ErrJobNotFound
+	}
+	if err := authorize(actor, policy.JobDelete, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return err
+	}
+	job.Status = domain.JobStatusRemoved
+	job.HoldReason = ""
+	return a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.UpdateStatus(ctx, jobID, domain.JobStatusRemoved, ""); err != nil {
+			return err
+		}
+		return a.events.Publish(ctx, job.CreatedBy, domain.EventJobClosed, domain.JobClosedData{Job: job, Reason: "deleted"})
+	})
+}
+
+// GetJobByID returns published jobs to anyone; jobs hidden from the public
+// are only returned to actors allowed to see them.
+func (a *jobApp) GetJobByID(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error) {
+	ctx, span := startSpan(ctx, "JobApp.GetJobByID")
+	defer span.End()
+	job, err := a.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	if !job.Visible() && !policy.Can(actor, policy.JobViewHidden, policy.Resource{OwnerID: job.CreatedBy.String()}) {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
 }
 
-func (a *jobApp) GetJobsByCompany(ctx context.Context, companyID string, page, size int) ([]domain.Job, int64, error) {
-	return a.repo.FindByCompany(ctx, companyID, page, size)
+func (a *jobApp) GetJobsByCompany(ctx context.Context, companyID string, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "JobApp.GetJobsByCompany")
+	defer span.End()
+	return a.repo.FindByCompany(ctx, companyID, q)
 }
 
-func (a *jobApp) SearchJobs(ctx context.Context, filters map[string]interface{}, page, size int) ([]domain.Job, int64, error) {
-	return a.repo.Search(ctx, filters, page, size)
+func (a *jobApp) SearchJobs(ctx context.Context, filters map[string]interface{}, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "JobApp.SearchJobs")
+	defer span.End()
+	return a.repo.Search(ctx, filters, q)
 }
@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/metrics"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+)
+
+// MaxImportJobs caps the jobs in one bulk import
+const MaxImportJobs = 500
+
+// JobInput is one job read from a bulk import file. Problems holds what was
+// wrong with the row before validation, such as a malformed CSV line.
+type JobInput struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	Problems    []string `json:"-"`
+}
+
+// ValidateJob applies the rules every job must meet to be posted
+func ValidateJob(title, description string) []string {
+	var errs []string
+	if len(title) < 1 || len(title) > 100 {
+		errs = append(errs, "Title must be 1-100 characters")
+	}
+	if len(description) < 20 || len(description) > 2000 {
+		errs = append(errs, "Description must be 20-2000 characters")
+	}
+	return errs
+}
+
+// ParseJobsJSON reads a JSON array of jobs
+func ParseJobsJSON(r io.Reader) ([]JobInput, error) {
+	var inputs []JobInput
+	if err := json.NewDecoder(r).Decode(&inputs); err != nil {
+		var syntax *json.SyntaxError
+		var typ *json.UnmarshalTypeError
+		if errors.As(err, &syntax) || errors.As(err, &typ) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ValidationError{"Body must be a JSON array of jobs with title, description and location"}
+		}
+		return nil, err
+	}
+	return inputs, nil
+}
+
+// ParseJobsCSV reads CSV with a header row naming the title, description
+// and location columns, in any order. Lines with the wrong number of cells
+// are kept as rows with a problem so they show up in the report.
+func ParseJobsCSV(r io.Reader) ([]JobInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	var perr *csv.ParseError
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) || errors.As(err, &perr) {
+		return nil, ValidationError{"CSV must start with a header row: title,description,location"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"title", "description"} {
+		if _, ok := columns[required]; !ok {
+			return nil, ValidationError{fmt.Sprintf("CSV header has no %s column", required)}
+		}
+	}
+	var inputs []JobInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return inputs, nil
+		}
+		if errors.As(err, &perr) && perr.Err == csv.ErrFieldCount {
+			err = nil
+		}
+		if errors.As(err, &perr) {
+			return nil, ValidationError{fmt.Sprintf("CSV could not be read: %v", err)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		cell := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+		input := JobInput{Title: cell("title"), Description: cell("description"), Location: cell("location")}
+		if len(record) != len(header) {
+			input.Problems = []string{fmt.Sprintf("Row has %d cells but the header has %d", len(record), len(header))}
+		}
+		inputs = append(inputs, input)
+	}
+}
+
+// ImportJobs validates every input like CreateJob and stores the valid ones
+// in a single transaction; invalid rows are reported and skipped. With
+// dryRun nothing is stored. Imported jobs go through the auto-hold rules,
+// counting the jobs earlier in the same import towards the new-account
+// limit.
+func (a *jobApp) ImportJobs(ctx context.Context, actor policy.Actor, inputs []JobInput, dryRun bool) (*domain.JobImportResult, error) {
+	ctx, span := startSpan(ctx, "JobApp.ImportJobs")
+	defer span.End()
+	companyID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	if err := authorize(actor, policy.JobCreate, policy.Resource{OwnerID: actor.ID}); err != nil {
+		return nil, err
+	}
+	if len(inputs) == 0 {
+		return nil, ValidationError{"No jobs to import"}
+	}
+	if len(inputs) > MaxImportJobs {
+		return nil, ValidationError{fmt.Sprintf("At most %d jobs can be imported at once", MaxImportJobs)}
+	}
+	history, err := a.history(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.JobImportResult{DryRun: dryRun, Total: len(inputs), Rows: make([]domain.JobImportRow, 0, len(inputs))}
+	var jobs []domain.Job
+	for i, input := range inputs {
+		row := domain.JobImportRow{Row: i + 1, Title: input.Title}
+		row.Errors = append(append(row.Errors, input.Problems...), ValidateJob(input.Title, input.Description)...)
+		if len(row.Errors) > 0 {
+			result.Invalid++
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+		job := domain.Job{
+			ID:          uuid.New(),
+			Title:       input.Title,
+			Description: input.Description,
+			Location:    strings.TrimSpace(input.Location),
+			CreatedBy:   companyID,
+			Status:      domain.JobStatusPublished,
+		}
+		a.hold(&job, history)
+		history.RecentJobs++
+		row.Status = job.Status
+		if !dryRun {
+			id := job.ID
+			row.JobID = &id
+		}
+		jobs = append(jobs, job)
+		result.Rows = append(result.Rows, row)
+	}
+	if dryRun || len(jobs) == 0 {
+		return result, nil
+	}
+	if err := a.repo.CreateMany(ctx, jobs); err != nil {
+		return nil, errors.New("Failed to import jobs")
+	}
+	result.Imported = len(jobs)
+	for _, job := range jobs {
+		metrics.JobsCreated.WithLabelValues(string(job.Status)).Inc()
+	}
+	return result, nil
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"github.com/yesetoda/Sera_Ale/internal/service"
+)
+
+const (
+	maxMessageLength   = 5000
+	maxAttachmentBytes = 10 << 20
+	messagePreviewLen  = 200
+)
+
+// attachmentTypes are the file extensions accepted as message attachments
+var attachmentTypes = []string{".pdf", ".doc", ".docx", ".txt", ".png", ".jpg", ".jpeg"}
+
+var ErrMessagesHidden = errors.New("Messages are no longer available for this application")
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	File interface{}
+	Name string
+	Size int64
+}
+
+// MessageApp runs the message thread of each application. Only the
+// applicant and the company that owns the job take part, and the thread is
+// hidden once the application is withdrawn or the job is gone.
+type MessageApp interface {
+	SendMessage(ctx context.Context, actor policy.Actor, applicationID, body string, attachment *Attachment) (*domain.Message, error)
+	Thread(ctx context.Context, actor policy.Actor, applicationID string, q domain.PageQuery) ([]domain.Message, domain.PageInfo, error)
+	MarkRead(ctx context.Context, actor policy.Actor, applicationID string) error
+	UnreadCounts(ctx context.Context, actor policy.Actor) ([]domain.UnreadCount, int64, error)
+}
+
+type messageApp struct {
+	repo          repository.MessageRepository
+	applications  repository.ApplicationRepository
+	jobs          repository.JobRepository
+	storage       service.CloudinaryService
+	tx            repository.Transactor
+	notifications NotificationApp
+}
+
+func NewMessageApp(repo repository.MessageRepository, applications repository.ApplicationRepository, jobs repository.JobRepository, storage service.CloudinaryService, tx repository.Transactor, notifications NotificationApp) MessageApp {
+	return &messageApp{repo: repo, applications: applications, jobs: jobs, storage: storage, tx: tx, notifications: notifications}
+}
+
+// thread is an application's conversation as seen by one participant
+type thread struct {
+	application *domain.Application
+	job         *domain.Job
+	// other is the participant who is not the actor.
+	other uuid.UUID
+}
+
+// openThread checks access the same way as ApplicationApp.UpdateStatus and
+// refuses threads that are hidden
+func (a *messageApp) openThread(ctx context.Context, actor policy.Actor, applicationID string) (*thread, error) {
+	application, err := a.applications.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	job, err := a.jobs.FindByID(ctx, application.JobID.String())
+	if err != nil {
+		// Jobs are only ever marked removed, never deleted, so this is a
+		// dangling reference.
+		return nil, ErrApplicationNotFound
+	}
+	res := policy.Resource{OwnerID: job.CreatedBy.String(), SubjectID: application.ApplicantID.String()}
+	if err := authorize(actor, policy.ApplicationMessage, res); err != nil {
+		return nil, err
+	}
+	if application.Status == domain.StatusWithdrawn || job.Status == domain.JobStatusRemoved {
+		return nil, ErrMessagesHidden
+	}
+	other := application.ApplicantID
+	if actor.ID == application.ApplicantID.String() {
+		other = job.CreatedBy
+	}
+	return &thread{application: application, job: job, other: other}, nil
+}
+
+// SendMessage posts to an application's thread. A message needs a body, an
+// attachment or both; the attachment is uploaded first and removed again if
+// the message cannot be stored. The message and the recipient's notification
+// are stored in one transaction.
+func (a *messageApp) SendMessage(ctx context.Context, actor policy.Actor, applicationID, body string, attachment *Attachment) (*domain.Message, error) {
+	ctx, span := startSpan(ctx, "MessageApp.SendMessage")
+	defer span.End()
+	t, err := a.openThread(ctx, actor, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	body = strings.TrimSpace(body)
+	var errs []string
+	if body == "" && attachment == nil {
+		errs = append(errs, "Write a message or attach a file")
+	}
+	if utf8.RuneCountInString(body) > maxMessageLength {
+		errs = append(errs, fmt.Sprintf("Message must be at most %d characters", maxMessageLength))
+	}
+	if attachment != nil {
+		if attachment.Size > maxAttachmentBytes {
+			errs = append(errs, fmt.Sprintf("Attachment must be at most %d MB", maxAttachmentBytes>>20))
+		}
+		if !slices.Contains(attachmentTypes, strings.ToLower(filepath.Ext(attachment.Name))) {
+			errs = append(errs, "Attachment must be one of "+strings.Join(attachmentTypes, ", "))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	message := &domain.Message{
+		ID:            uuid.New(),
+		ApplicationID: t.application.ID,
+		SenderID:      sender,
+		RecipientID:   t.other,
+		Body:          body,
+	}
+	var publicID string
+	if attachment != nil {
+		publicID = uuid.New().String()
+		url, err := a.storage.UploadAttachment(ctx, attachment.File, publicID)
+		if err != nil {
+			slog.ErrorContext(ctx, "attachment upload failed", "error", err)
+			return nil, errors.New("Failed to upload attachment")
+		}
+		message.AttachmentURL = url
+		message.AttachmentName = filepath.Base(attachment.Name)
+	}
+	preview := body
+	if utf8.RuneCountInString(preview) > messagePreviewLen {
+		preview = string([]rune(preview)[:messagePreviewLen]) + "…"
+	}
+	if preview == "" {
+		preview = "Sent a file: " + message.AttachmentName
+	}
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.repo.Create(ctx, message); err != nil {
+			return err
+		}
+		return a.notifications.Notify(ctx, t.other, domain.NotifyMessage, fmt.Sprintf("New message about %s", t.job.Title), preview, map[string]string{
+			"application_id": t.application.ID.String(),
+			"job_id":         t.job.ID.String(),
+			"message_id":     message.ID.String(),
+		})
+	})
+	if err != nil {
+		if publicID != "" {
+			if derr := a.storage.DeleteAttachment(context.WithoutCancel(ctx), publicID); derr != nil {
+				slog.ErrorContext(ctx, "failed to delete orphaned attachment", "public_id", publicID, "error", derr)
+			}
+		}
+		return nil, errors.New("Failed to send message")
+	}
+	return message, nil
+}
+
+// Thread lists an application's messages, newest first
+func (a *messageApp) Thread(ctx context.Context, actor policy.Actor, applicationID string, q domain.PageQuery) ([]domain.Message, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "MessageApp.Thread")
+	defer span.End()
+	if _, err := a.openThread(ctx, actor, applicationID); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindByApplication(ctx, applicationID, q)
+}
+
+// MarkRead marks every message the actor received in the thread as read
+func (a *messageApp) MarkRead(ctx context.Context, actor policy.Actor, applicationID string) error {
+	ctx, span := startSpan(ctx, "MessageApp.MarkRead")
+	defer span.End()
+	if _, err := a.openThread(ctx, actor, applicationID); err != nil {
+		return err
+	}
+	return a.repo.MarkRead(ctx, applicationID, actor.ID)
+}
+
+// UnreadCounts returns the actor's unread messages per visible thread and
+// their total
+func (a *messageApp) UnreadCounts(ctx context.Context, actor policy.Actor) ([]domain.UnreadCount, int64, error) {
+	ctx, span := startSpan(ctx, "MessageApp.UnreadCounts")
+	defer span.End()
+	if !policy.Allows(actor.Role, policy.ApplicationMessage) {
+		return nil, 0, ErrUnauthorized
+	}
+	counts, err := a.repo.CountUnread(ctx, actor.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	for _, c := range counts {
+		total += c.Unread
+	}
+	if counts == nil {
+		counts = []domain.UnreadCount{}
+	}
+	return counts, total, nil
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/moderation"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyReported = errors.New("You have already reported this job")
+	ErrNotInQueue      = errors.New("Job is not awaiting moderation")
+)
+
+type ModerationApp interface {
+	ReportJob(ctx context.Context, actor policy.Actor, jobID, reason, details string) (*domain.JobReport, error)
+	Queue(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.ModerationItem, domain.PageInfo, error)
+	Approve(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error)
+	Reject(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error)
+}
+
+type moderationApp struct {
+	jobs    repository.JobRepository
+	reports repository.ReportRepository
+	rules   moderation.Rules
+	tx      repository.Transactor
+	events  EventPublisher
+}
+
+func NewModerationApp(jobs repository.JobRepository, reports repository.ReportRepository, rules moderation.Rules, tx repository.Transactor, events EventPublisher) ModerationApp {
+	return &moderationApp{jobs: jobs, reports: reports, rules: rules, tx: tx, events: events}
+}
+
+// ReportJob records a report and holds the job once it reaches the
+// configured number of unresolved reports
+func (a *moderationApp) ReportJob(ctx context.Context, actor policy.Actor, jobID, reason, details string) (*domain.JobReport, error) {
+	ctx, span := startSpan(ctx, "ModerationApp.ReportJob")
+	defer span.End()
+	reportReason := parseReportReason(reason)
+	if !reportReason.Valid() {
+		return nil, ValidationError{"Reason must be one of scam, discriminatory, spam or other"}
+	}
+	if len(details) > 1000 {
+		return nil, ValidationError{"Details must be under 1000 characters"}
+	}
+	job, err := a.jobs.FindByID(ctx, jobID)
+	if err != nil || !job.Visible() {
+		return nil, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.JobReport, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, err
+	}
+	reporterID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	report := &domain.JobReport{
+		ID:         uuid.New(),
+		JobID:      job.ID,
+		ReporterID: reporterID,
+		Reason:     reportReason,
+		Details:    details,
+	}
+	// The report and the hold it may trigger are stored together, so a job
+	// never ends up over the threshold but still published.
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.reports.Create(ctx, report); err != nil {
+			return err
+		}
+		if a.rules.ReportThreshold <= 0 {
+			return nil
+		}
+		open, err := a.reports.CountOpenByJob(ctx, jobID)
+		if err != nil || open < a.rules.ReportThreshold {
+			return err
+		}
+		return a.jobs.UpdateStatus(ctx, jobID, domain.JobStatusHeld, "reported by users")
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, errors.New("Failed to report job")
+	}
+	return report, nil
+}
+
+func (a *moderationApp) Queue(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.ModerationItem, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "ModerationApp.Queue")
+	defer span.End()
+	if err := authorize(actor, policy.JobModerate, policy.Resource{}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	jobs, info, err := a.jobs.FindForModeration(ctx, q)
+	if err != nil {
+		return nil, info, err
+	}
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	reports, err := a.reports.FindOpenByJobs(ctx, ids)
+	if err != nil {
+		return nil, info, err
+	}
+	byJob := make(map[uuid.UUID][]domain.JobReport, len(jobs))
+	for _, report := range reports {
+		byJob[report.JobID] = append(byJob[report.JobID], report)
+	}
+	items := make([]domain.ModerationItem, 0, len(jobs))
+	for _, job := range jobs {
+		reports := byJob[job.ID]
+		if reports == nil {
+			reports = []domain.JobReport{}
+		}
+		items = append(items, domain.ModerationItem{Job: job, Reports: reports})
+	}
+	return items, info, nil
+}
+
+// Approve publishes a held job and dismisses its open reports
+func (a *moderationApp) Approve(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error) {
+	ctx, span := startSpan(ctx, "ModerationApp.Approve")
+	defer span.End()
+	return a.decide(ctx, actor, jobID, domain.JobStatusPublished)
+}
+
+// Reject removes a held or reported job and closes its open reports
+func (a *moderationApp) Reject(ctx context.Context, actor policy.Actor, jobID string) (*domain.Job, error) {
+	ctx, span := startSpan(ctx, "ModerationApp.Reject")
+	defer span.End()
+	return a.decide(ctx, actor, jobID, domain.JobStatusRemoved)
+}
+
+func (a *moderationApp) decide(ctx context.Context, actor policy.Actor, jobID string, status domain.JobStatus) (*domain.Job, error) {
+	job, err := a.jobs.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	if err := authorize(actor, policy.JobModerate, policy.Resource{OwnerID: job.CreatedBy.String()}); err != nil {
+		return nil, err
+	}
+	if job.Status == domain.JobStatusRemoved {
+		return nil, ErrNotInQueue
+	}
+	// Only jobs in the queue can be decided on: held ones and published ones
+	// with open reports.
+	if job.Status != domain.JobStatusHeld {
+		open, err := a.reports.CountOpenByJob(ctx, jobID)
+		if err != nil {
+			return nil, errors.New("Failed to load reports")
+		}
+		if open == 0 {
+			return nil, ErrNotInQueue
+		}
+	}
+	job.Status = status
+	job.HoldReason = ""
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if err := a.jobs.UpdateStatus(ctx, jobID, status, ""); err != nil {
+			return err
+		}
+		if err := a.reports.ResolveByJob(ctx, jobID); err != nil {
+			return err
+		}
+		if status != domain.JobStatusRemoved {
+			return nil
+		}
+		return a.events.Publish(ctx, job.CreatedBy, domain.EventJobClosed, domain.JobClosedData{Job: job, Reason: "removed"})
+	})
+	if err != nil {
+		return nil, errors.New("Failed to update job")
+	}
+	return job, nil
+}
+
+// parseReportReason normalises user input such as "Scam" to a ReportReason
+func parseReportReason(s string) domain.ReportReason {
+	return domain.ReportReason(strings.ToLower(strings.TrimSpace(s)))
+}
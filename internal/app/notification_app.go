@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"github.com/yesetoda/Sera_Ale/internal/service"
+)
+
+const (
+	emailBatchSize   = 20
+	emailTimeout     = 30 * time.Second
+	maxEmailAttempts = 5
+)
+
+var ErrNotificationNotFound = errors.New("Notification not found")
+
+// NotificationApp turns domain events into notifications for the users they
+// concern, serves the in-app inbox and sends the matching emails.
+type NotificationApp interface {
+	EventPublisher
+	Notify(ctx context.Context, userID uuid.UUID, t domain.NotificationType, title, body string, data map[string]string) error
+	Inbox(ctx context.Context, actor policy.Actor, unreadOnly bool, q domain.PageQuery) ([]domain.Notification, domain.PageInfo, error)
+	UnreadCount(ctx context.Context, actor policy.Actor) (int64, error)
+	MarkRead(ctx context.Context, actor policy.Actor, notificationID string) (*domain.Notification, error)
+	MarkAllRead(ctx context.Context, actor policy.Actor) error
+	Preferences(ctx context.Context, actor policy.Actor) ([]domain.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, actor policy.Actor, prefs []domain.NotificationPreference) ([]domain.NotificationPreference, error)
+	SendPendingEmails(ctx context.Context)
+}
+
+type notificationApp struct {
+	repo   repository.NotificationRepository
+	users  repository.UserRepository
+	mailer service.Mailer
+}
+
+// NewNotificationApp builds the notification app. A nil mailer disables
+// email; notifications then only go to the inbox.
+func NewNotificationApp(repo repository.NotificationRepository, users repository.UserRepository, mailer service.Mailer) NotificationApp {
+	return &notificationApp{repo: repo, users: users, mailer: mailer}
+}
+
+// Publish notifies the company about new applications and the applicant
+// about status changes. Other events are ignored. The notifications are
+// stored in the transaction carried by ctx, next to the change itself.
+func (a *notificationApp) Publish(ctx context.Context, companyID uuid.UUID, event domain.WebhookEvent, data interface{}) error {
+	ctx, span := startSpan(ctx, "NotificationApp.Publish")
+	defer span.End()
+	d, ok := data.(domain.ApplicationEventData)
+	if !ok || d.Application == nil || d.Job == nil {
+		return nil
+	}
+	refs := map[string]string{"application_id": d.Application.ID.String(), "job_id": d.Job.ID.String()}
+	switch event {
+	case domain.EventApplicationCreated:
+		return a.Notify(ctx, companyID, domain.NotifyApplicationReceived,
+			fmt.Sprintf("New application for %s", d.Job.Title),
+			fmt.Sprintf("Someone applied to %s. Review it from your applications list.", d.Job.Title), refs)
+	case domain.EventApplicationStatusChanged:
+		if d.Application.Status == domain.StatusWithdrawn {
+			return a.Notify(ctx, companyID, domain.NotifyApplicationStatus,
+				fmt.Sprintf("Application for %s withdrawn", d.Job.Title),
+				fmt.Sprintf("An applicant withdrew their application for %s.", d.Job.Title), refs)
+		}
+		title := fmt.Sprintf("Your application for %s is now %s", d.Job.Title, d.Application.Status)
+		body := fmt.Sprintf("The status of your application for %s changed from %s to %s.", d.Job.Title, d.PreviousStatus, d.Application.Status)
+		if d.Application.Status == domain.StatusInterview {
+			title = fmt.Sprintf("You have been invited to interview for %s", d.Job.Title)
+		}
+		return a.Notify(ctx, d.Application.ApplicantID, domain.NotifyApplicationStatus, title, body, refs)
+	}
+	return nil
+}
+
+// Notify stores a notification for userID according to their preference
+// for t. The row is kept even when only the email was asked for, as it
+// carries the email until it is sent. Failures are logged and returned, so
+// callers can store the notification in the transaction of the change it
+// is about and roll both back together.
+func (a *notificationApp) Notify(ctx context.Context, userID uuid.UUID, t domain.NotificationType, title, body string, data map[string]string) error {
+	pref, err := a.preference(ctx, userID, t)
+	if err != nil {
+		slog.ErrorContext(ctx, "notification: failed to load preferences", "user_id", userID, "type", t, "error", err)
+		return err
+	}
+	sendEmail := pref.Email && a.mailer != nil
+	if !pref.InApp && !sendEmail {
+		return nil
+	}
+	n := &domain.Notification{
+		ID:                 uuid.New(),
+		UserID:             userID,
+		Type:               t,
+		Title:              title,
+		Body:               body,
+		Data:               data,
+		InInbox:            pref.InApp,
+		EmailStatus:        domain.EmailSkipped,
+		EmailNextAttemptAt: time.Now(),
+	}
+	if sendEmail {
+		n.EmailStatus = domain.EmailPending
+	}
+	if err := a.repo.Create(ctx, n); err != nil {
+		slog.ErrorContext(ctx, "notification: failed to store notification", "user_id", userID, "type", t, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (a *notificationApp) preference(ctx context.Context, userID uuid.UUID, t domain.NotificationType) (domain.NotificationPreference, error) {
+	prefs, err := a.repo.FindPreferences(ctx, userID.String())
+	if err != nil {
+		return domain.NotificationPreference{}, err
+	}
+	for _, p := range prefs {
+		if p.Type == t {
+			return p, nil
+		}
+	}
+	return domain.DefaultNotificationPreference(userID, t), nil
+}
+
+func (a *notificationApp) Inbox(ctx context.Context, actor policy.Actor, unreadOnly bool, q domain.PageQuery) ([]domain.Notification, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "NotificationApp.Inbox")
+	defer span.End()
+	if err := authorize(actor, policy.NotificationRead, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindInbox(ctx, actor.ID, unreadOnly, q)
+}
+
+func (a *notificationApp) UnreadCount(ctx context.Context, actor policy.Actor) (int64, error) {
+	ctx, span := startSpan(ctx, "NotificationApp.UnreadCount")
+	defer span.End()
+	if err := authorize(actor, policy.NotificationRead, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return 0, err
+	}
+	return a.repo.CountUnread(ctx, actor.ID)
+}
+
+func (a *notificationApp) MarkRead(ctx context.Context, actor policy.Actor, notificationID string) (*domain.Notification, error) {
+	ctx, span := startSpan(ctx, "NotificationApp.MarkRead")
+	defer span.End()
+	n, err := a.repo.FindByID(ctx, notificationID)
+	if err != nil {
+		return nil, ErrNotificationNotFound
+	}
+	if err := authorize(actor, policy.NotificationRead, policy.Resource{SubjectID: n.UserID.String()}); err != nil {
+		return nil, err
+	}
+	if n.ReadAt != nil {
+		return n, nil
+	}
+	if err := a.repo.MarkRead(ctx, notificationID); err != nil {
+		return nil, errors.New("Failed to update notification")
+	}
+	now := time.Now()
+	n.ReadAt = &now
+	return n, nil
+}
+
+func (a *notificationApp) MarkAllRead(ctx context.Context, actor policy.Actor) error {
+	ctx, span := startSpan(ctx, "NotificationApp.MarkAllRead")
+	defer span.End()
+	if err := authorize(actor, policy.NotificationRead, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return err
+	}
+	return a.repo.MarkAllRead(ctx, actor.ID)
+}
+
+// Preferences returns the actor's preference for every notification type,
+// filling in defaults for types they never changed
+func (a *notificationApp) Preferences(ctx context.Context, actor policy.Actor) ([]domain.NotificationPreference, error) {
+	ctx, span := startSpan(ctx, "NotificationApp.Preferences")
+	defer span.End()
+	if err := authorize(actor, policy.NotificationRead, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	stored, err := a.repo.FindPreferences(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	byType := make(map[domain.NotificationType]domain.NotificationPreference, len(stored))
+	for _, p := range stored {
+		byType[p.Type] = p
+	}
+	prefs := make([]domain.NotificationPreference, 0, len(domain.NotificationTypes))
+	for _, t := range domain.NotificationTypes {
+		p, ok := byType[t]
+		if !ok {
+			p = domain.DefaultNotificationPreference(userID, t)
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences stores the given preferences; types left out keep their
+// current setting
+func (a *notificationApp) UpdatePreferences(ctx context.Context, actor policy.Actor, prefs []domain.NotificationPreference) ([]domain.NotificationPreference, error) {
+	ctx, span := startSpan(ctx, "NotificationApp.UpdatePreferences")
+	defer span.End()
+	if err := authorize(actor, policy.NotificationRead, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	var errs []string
+	for i := range prefs {
+		if !prefs[i].Type.Valid() {
+			errs = append(errs, fmt.Sprintf("Unknown notification type %q", prefs[i].Type))
+		}
+		prefs[i].UserID = userID
+	}
+	if len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	if err := a.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, errors.New("Failed to save preferences")
+	}
+	return a.Preferences(ctx, actor)
+}
+
+// SendPendingEmails sends every notification email that is due. Failed
+// emails are retried with a growing delay up to maxEmailAttempts. It is meant
+// to be run periodically from a worker.
+func (a *notificationApp) SendPendingEmails(ctx context.Context) {
+	if a.mailer == nil {
+		return
+	}
+	ctx, span := startSpan(ctx, "NotificationApp.SendPendingEmails")
+	defer span.End()
+	lease := emailBatchSize*emailTimeout + time.Minute
+	for ctx.Err() == nil {
+		pending, err := a.repo.ClaimPendingEmails(ctx, time.Now(), lease, emailBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "notification: failed to claim emails", "error", err)
+			}
+			return
+		}
+		for i := range pending {
+			a.sendEmail(ctx, &pending[i])
+		}
+		if len(pending) < emailBatchSize {
+			return
+		}
+	}
+}
+
+func (a *notificationApp) sendEmail(ctx context.Context, n *domain.Notification) {
+	user, err := a.users.FindByID(ctx, n.UserID.String())
+	if err == nil {
+		sendCtx, cancel := context.WithTimeout(ctx, emailTimeout)
+		err = a.mailer.Send(sendCtx, service.Mail{To: user.Email, Subject: n.Title, Body: n.Body})
+		cancel()
+	}
+	if ctx.Err() != nil {
+		// Shutting down: the email is retried once its lease expires.
+		return
+	}
+	n.EmailAttempts++
+	switch {
+	case err == nil:
+		n.EmailStatus = domain.EmailSent
+	case n.EmailAttempts >= maxEmailAttempts:
+		n.EmailStatus = domain.EmailFailed
+		slog.WarnContext(ctx, "notification: giving up on email", "notification_id", n.ID, "error", err)
+	default:
+		n.EmailNextAttemptAt = time.Now().Add(time.Minute << n.EmailAttempts)
+		slog.WarnContext(ctx, "notification: email failed, will retry", "notification_id", n.ID, "attempt", n.EmailAttempts, "error", err)
+	}
+	if err := a.repo.RecordEmail(ctx, n); err != nil {
+		slog.ErrorContext(ctx, "notification: failed to record email attempt", "notification_id", n.ID, "error", err)
+	}
+}
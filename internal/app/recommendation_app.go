@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/recommend"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+const (
+	// recommendationCandidates is how many of the newest open jobs are
+	// scored for each request
+	recommendationCandidates = 500
+	// recentApplications is how much application history feeds the scorer
+	recentApplications = 50
+	// DefaultRecommendations and MaxRecommendations bound the limit of a
+	// request
+	DefaultRecommendations = 20
+	MaxRecommendations     = 50
+)
+
+// RecommendationApp suggests open jobs to applicants
+type RecommendationApp interface {
+	Recommend(ctx context.Context, actor policy.Actor, limit int) ([]recommend.Recommendation, error)
+}
+
+type recommendationApp struct {
+	users        repository.UserRepository
+	jobs         repository.JobRepository
+	applications repository.ApplicationRepository
+	searches     repository.SavedSearchRepository
+}
+
+func NewRecommendationApp(users repository.UserRepository, jobs repository.JobRepository, applications repository.ApplicationRepository, searches repository.SavedSearchRepository) RecommendationApp {
+	return &recommendationApp{users: users, jobs: jobs, applications: applications, searches: searches}
+}
+
+// Recommend ranks the newest open jobs the actor has not applied to by their
+// profile, saved searches and application history
+func (a *recommendationApp) Recommend(ctx context.Context, actor policy.Actor, limit int) ([]recommend.Recommendation, error) {
+	ctx, span := startSpan(ctx, "RecommendationApp.Recommend")
+	defer span.End()
+	if err := authorize(actor, policy.JobSearch, policy.Resource{}); err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = DefaultRecommendations
+	}
+	if limit > MaxRecommendations {
+		limit = MaxRecommendations
+	}
+	user, err := a.users.FindByID(ctx, actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	searches, _, err := a.searches.FindByApplicant(ctx, actor.ID, domain.PageQuery{Page: 1, Size: maxSavedSearches})
+	if err != nil {
+		return nil, err
+	}
+	applied, err := a.appliedJobs(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := a.jobs.FindOpenForApplicant(ctx, actor.ID, recommendationCandidates)
+	if err != nil {
+		return nil, err
+	}
+	signals := recommend.Signals{
+		Skills:   user.Skills,
+		Location: user.Location,
+		Searches: searches,
+		Applied:  applied,
+	}
+	return recommend.Rank(signals, candidates, limit), nil
+}
+
+// appliedJobs loads the jobs behind the actor's most recent applications
+func (a *recommendationApp) appliedJobs(ctx context.Context, applicantID string) ([]domain.Job, error) {
+	apps, _, err := a.applications.FindByApplicant(ctx, applicantID, domain.PageQuery{Page: 1, Size: recentApplications})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(apps))
+	for _, app := range apps {
+		ids = append(ids, app.JobID)
+	}
+	return a.jobs.FindByIDs(ctx, ids)
+}
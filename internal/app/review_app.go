@@ -0,0 +1,215 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+const (
+	maxNoteLength = 2000
+	maxTags       = 20
+	maxTagLength  = 30
+)
+
+var ErrNoteNotFound = errors.New("Note not found")
+
+// ReviewApp keeps a company's private notes, ratings and tags on the
+// applications sent to its jobs. Access follows the same ownership rule as
+// ApplicationApp.UpdateStatus, and none of it is shown to applicants.
+type ReviewApp interface {
+	GetReview(ctx context.Context, actor policy.Actor, applicationID string) (*domain.ApplicationReview, error)
+	AddNote(ctx context.Context, actor policy.Actor, applicationID, body string) (*domain.ApplicationNote, error)
+	DeleteNote(ctx context.Context, actor policy.Actor, applicationID, noteID string) error
+	Rate(ctx context.Context, actor policy.Actor, applicationID string, rating int) (*domain.ApplicationReview, error)
+	SetTags(ctx context.Context, actor policy.Actor, applicationID string, tags []string) (*domain.ApplicationReview, error)
+}
+
+type reviewApp struct {
+	repo         repository.ReviewRepository
+	applications repository.ApplicationRepository
+	jobs         repository.JobRepository
+}
+
+func NewReviewApp(repo repository.ReviewRepository, applications repository.ApplicationRepository, jobs repository.JobRepository) ReviewApp {
+	return &reviewApp{repo: repo, applications: applications, jobs: jobs}
+}
+
+// owned loads an application the actor may review
+func (a *reviewApp) owned(ctx context.Context, actor policy.Actor, applicationID string) (*domain.Application, error) {
+	application, err := a.applications.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	job, err := a.jobs.FindByID(ctx, application.JobID.String())
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	res := policy.Resource{OwnerID: job.CreatedBy.String(), SubjectID: application.ApplicantID.String()}
+	if err := authorize(actor, policy.ApplicationReview, res); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func (a *reviewApp) GetReview(ctx context.Context, actor policy.Actor, applicationID string) (*domain.ApplicationReview, error) {
+	ctx, span := startSpan(ctx, "ReviewApp.GetReview")
+	defer span.End()
+	application, err := a.owned(ctx, actor, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	return a.review(ctx, application.ID)
+}
+
+func (a *reviewApp) AddNote(ctx context.Context, actor policy.Actor, applicationID, body string) (*domain.ApplicationNote, error) {
+	ctx, span := startSpan(ctx, "ReviewApp.AddNote")
+	defer span.End()
+	application, err := a.owned(ctx, actor, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	author, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ValidationError{"Note must not be empty"}
+	}
+	if utf8.RuneCountInString(body) > maxNoteLength {
+		return nil, ValidationError{fmt.Sprintf("Note must be at most %d characters", maxNoteLength)}
+	}
+	note := &domain.ApplicationNote{ID: uuid.New(), ApplicationID: application.ID, AuthorID: author, Body: body}
+	if err := a.repo.CreateNote(ctx, note); err != nil {
+		return nil, errors.New("Failed to add note")
+	}
+	return note, nil
+}
+
+// DeleteNote removes a note; only its author can
+func (a *reviewApp) DeleteNote(ctx context.Context, actor policy.Actor, applicationID, noteID string) error {
+	ctx, span := startSpan(ctx, "ReviewApp.DeleteNote")
+	defer span.End()
+	application, err := a.owned(ctx, actor, applicationID)
+	if err != nil {
+		return err
+	}
+	note, err := a.repo.FindNote(ctx, noteID)
+	if err != nil || note.ApplicationID != application.ID {
+		return ErrNoteNotFound
+	}
+	if note.AuthorID.String() != actor.ID {
+		return ErrUnauthorized
+	}
+	if err := a.repo.DeleteNote(ctx, noteID); err != nil {
+		return errors.New("Failed to delete note")
+	}
+	return nil
+}
+
+// Rate records the actor's 1-5 rating, replacing their earlier one
+func (a *reviewApp) Rate(ctx context.Context, actor policy.Actor, applicationID string, rating int) (*domain.ApplicationReview, error) {
+	ctx, span := startSpan(ctx, "ReviewApp.Rate")
+	defer span.End()
+	application, err := a.owned(ctx, actor, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	reviewer, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	if rating < 1 || rating > 5 {
+		return nil, ValidationError{"Rating must be between 1 and 5"}
+	}
+	if err := a.repo.SaveRating(ctx, &domain.ApplicationRating{ApplicationID: application.ID, ReviewerID: reviewer, Rating: rating}); err != nil {
+		return nil, errors.New("Failed to save rating")
+	}
+	return a.review(ctx, application.ID)
+}
+
+// SetTags replaces the application's tags
+func (a *reviewApp) SetTags(ctx context.Context, actor policy.Actor, applicationID string, tags []string) (*domain.ApplicationReview, error) {
+	ctx, span := startSpan(ctx, "ReviewApp.SetTags")
+	defer span.End()
+	application, err := a.owned(ctx, actor, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	tags, errs := normalizeTags(tags)
+	if len(tags) > maxTags {
+		errs = append(errs, fmt.Sprintf("At most %d tags are allowed", maxTags))
+	}
+	if len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	if err := a.repo.ReplaceTags(ctx, application.ID, tags); err != nil {
+		return nil, errors.New("Failed to save tags")
+	}
+	return a.review(ctx, application.ID)
+}
+
+func (a *reviewApp) review(ctx context.Context, applicationID uuid.UUID) (*domain.ApplicationReview, error) {
+	id := applicationID.String()
+	notes, err := a.repo.FindNotes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := a.repo.FindRatings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := a.repo.FindTags(ctx, []uuid.UUID{applicationID})
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := a.repo.RatingSummaries(ctx, []uuid.UUID{applicationID})
+	if err != nil {
+		return nil, err
+	}
+	review := &domain.ApplicationReview{
+		ApplicationID: applicationID,
+		Notes:         notes,
+		Ratings:       ratings,
+		Tags:          tags[applicationID],
+		RatingSummary: summaries[applicationID],
+	}
+	if review.Notes == nil {
+		review.Notes = []domain.ApplicationNote{}
+	}
+	if review.Ratings == nil {
+		review.Ratings = []domain.ApplicationRating{}
+	}
+	if review.Tags == nil {
+		review.Tags = []string{}
+	}
+	return review, nil
+}
+
+// normalizeTags trims, lowercases and dedups tags, keeping their order
+func normalizeTags(tags []string) ([]string, []string) {
+	var errs []string
+	seen := map[string]bool{}
+	cleaned := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			errs = append(errs, fmt.Sprintf("Tag %q must be at most %d characters", tag, maxTagLength))
+			continue
+		}
+		seen[tag] = true
+		cleaned = append(cleaned, tag)
+	}
+	return cleaned, errs
+}
@@ -0,0 +1,298 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+)
+
+const (
+	// maxSavedSearches bounds the alert work done for a single applicant
+	maxSavedSearches = 20
+	// maxDigestJobs is the most jobs listed in one digest; the rest follow
+	// in the next one
+	maxDigestJobs  = 20
+	alertBatchSize = 50
+	alertLease     = 10 * time.Minute
+)
+
+var (
+	ErrSavedSearchNotFound  = errors.New("Saved search not found")
+	ErrTooManySavedSearches = fmt.Errorf("An applicant can save at most %d searches", maxSavedSearches)
+)
+
+// SavedSearchApp stores applicants' job searches and sends them digests of
+// new matching jobs through the notification app.
+type SavedSearchApp interface {
+	CreateSavedSearch(ctx context.Context, actor policy.Actor, search *domain.SavedSearch) error
+	ListSavedSearches(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.SavedSearch, domain.PageInfo, error)
+	UpdateSavedSearch(ctx context.Context, actor policy.Actor, changes *domain.SavedSearch) (*domain.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, actor policy.Actor, searchID string) error
+	RunSavedSearch(ctx context.Context, actor policy.Actor, searchID string, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error)
+	Unsubscribe(ctx context.Context, token string) (*domain.SavedSearch, error)
+	SendAlerts(ctx context.Context)
+}
+
+type savedSearchApp struct {
+	repo          repository.SavedSearchRepository
+	jobs          repository.JobRepository
+	tx            repository.Transactor
+	notifications NotificationApp
+	publicURL     string
+}
+
+// NewSavedSearchApp builds the saved search app. publicURL is the address
+// of this API, used for the job and unsubscribe links in digests.
+func NewSavedSearchApp(repo repository.SavedSearchRepository, jobs repository.JobRepository, tx repository.Transactor, notifications NotificationApp, publicURL string) SavedSearchApp {
+	return &savedSearchApp{repo: repo, jobs: jobs, tx: tx, notifications: notifications, publicURL: strings.TrimRight(publicURL, "/")}
+}
+
+func (a *savedSearchApp) CreateSavedSearch(ctx context.Context, actor policy.Actor, search *domain.SavedSearch) error {
+	ctx, span := startSpan(ctx, "SavedSearchApp.CreateSavedSearch")
+	defer span.End()
+	if err := authorize(actor, policy.SavedSearchManage, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return err
+	}
+	applicantID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return ErrUnauthorized
+	}
+	if search.Frequency == "" {
+		search.Frequency = domain.AlertDaily
+	}
+	if errs := validateSavedSearch(search); len(errs) > 0 {
+		return ValidationError(errs)
+	}
+	count, err := a.repo.CountByApplicant(ctx, actor.ID)
+	if err != nil {
+		return err
+	}
+	if count >= maxSavedSearches {
+		return ErrTooManySavedSearches
+	}
+	token, err := newUnsubscribeToken()
+	if err != nil {
+		return err
+	}
+	search.ID = uuid.New()
+	search.ApplicantID = applicantID
+	search.UnsubscribeToken = token
+	search.NextAlertAt = time.Now().Add(search.Frequency.Interval())
+	search.LastAlertAt = nil
+	if err := a.repo.Create(ctx, search); err != nil {
+		return errors.New("Failed to save search")
+	}
+	return nil
+}
+
+// validateSavedSearch trims the user-supplied fields of search and lists
+// what is wrong with them
+func validateSavedSearch(search *domain.SavedSearch) []string {
+	search.Name = strings.TrimSpace(search.Name)
+	search.Title = strings.TrimSpace(search.Title)
+	search.Location = strings.TrimSpace(search.Location)
+	search.CompanyName = strings.TrimSpace(search.CompanyName)
+	var errs []string
+	if search.Name == "" {
+		errs = append(errs, "Name is required")
+	} else if len(search.Name) > 100 {
+		errs = append(errs, "Name must be at most 100 characters")
+	}
+	if len(search.Title) > 200 || len(search.Location) > 200 || len(search.CompanyName) > 200 {
+		errs = append(errs, "Filters must be at most 200 characters")
+	}
+	if !search.Frequency.Valid() {
+		errs = append(errs, "Frequency must be daily, weekly or off")
+	}
+	return errs
+}
+
+func newUnsubscribeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (a *savedSearchApp) ListSavedSearches(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.SavedSearch, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "SavedSearchApp.ListSavedSearches")
+	defer span.End()
+	if err := authorize(actor, policy.SavedSearchManage, policy.Resource{SubjectID: actor.ID}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindByApplicant(ctx, actor.ID, q)
+}
+
+// UpdateSavedSearch copies the name, filters and frequency of changes onto
+// the stored search identified by changes.ID. A new frequency restarts the
+// digest schedule from now.
+func (a *savedSearchApp) UpdateSavedSearch(ctx context.Context, actor policy.Actor, changes *domain.SavedSearch) (*domain.SavedSearch, error) {
+	ctx, span := startSpan(ctx, "SavedSearchApp.UpdateSavedSearch")
+	defer span.End()
+	search, err := a.owned(ctx, actor, changes.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if changes.Frequency == "" {
+		changes.Frequency = search.Frequency
+	}
+	if errs := validateSavedSearch(changes); len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	if changes.Frequency != search.Frequency {
+		search.NextAlertAt = time.Now().Add(changes.Frequency.Interval())
+	}
+	search.Name = changes.Name
+	search.Title = changes.Title
+	search.Location = changes.Location
+	search.CompanyName = changes.CompanyName
+	search.Frequency = changes.Frequency
+	if err := a.repo.Update(ctx, search); err != nil {
+		return nil, errors.New("Failed to update saved search")
+	}
+	return search, nil
+}
+
+func (a *savedSearchApp) DeleteSavedSearch(ctx context.Context, actor policy.Actor, searchID string) error {
+	ctx, span := startSpan(ctx, "SavedSearchApp.DeleteSavedSearch")
+	defer span.End()
+	if _, err := a.owned(ctx, actor, searchID); err != nil {
+		return err
+	}
+	return a.repo.Delete(ctx, searchID)
+}
+
+// RunSavedSearch returns what /applicant/jobs returns for the saved filters
+func (a *savedSearchApp) RunSavedSearch(ctx context.Context, actor policy.Actor, searchID string, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "SavedSearchApp.RunSavedSearch")
+	defer span.End()
+	search, err := a.owned(ctx, actor, searchID)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.jobs.Search(ctx, search.Filters(), q)
+}
+
+// owned loads a saved search the actor may manage
+func (a *savedSearchApp) owned(ctx context.Context, actor policy.Actor, searchID string) (*domain.SavedSearch, error) {
+	search, err := a.repo.FindByID(ctx, searchID)
+	if err != nil {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err := authorize(actor, policy.SavedSearchManage, policy.Resource{SubjectID: search.ApplicantID.String()}); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// Unsubscribe turns off the alerts of the search the token was issued for.
+// The token is the credential, so it works from an email without logging in.
+func (a *savedSearchApp) Unsubscribe(ctx context.Context, token string) (*domain.SavedSearch, error) {
+	ctx, span := startSpan(ctx, "SavedSearchApp.Unsubscribe")
+	defer span.End()
+	if token == "" {
+		return nil, ErrSavedSearchNotFound
+	}
+	search, err := a.repo.FindByUnsubscribeToken(ctx, token)
+	if err != nil {
+		return nil, ErrSavedSearchNotFound
+	}
+	if search.Frequency == domain.AlertOff {
+		return search, nil
+	}
+	search.Frequency = domain.AlertOff
+	if err := a.repo.Update(ctx, search); err != nil {
+		return nil, errors.New("Failed to update saved search")
+	}
+	return search, nil
+}
+
+// SendAlerts sends the digest of every saved search that is due. Searches
+// without new jobs are rescheduled without notifying anyone. It is meant to
+// be run periodically from a worker.
+func (a *savedSearchApp) SendAlerts(ctx context.Context) {
+	ctx, span := startSpan(ctx, "SavedSearchApp.SendAlerts")
+	defer span.End()
+	for ctx.Err() == nil {
+		due, err := a.repo.ClaimDue(ctx, time.Now(), alertLease, alertBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "job alert: failed to claim saved searches", "error", err)
+			}
+			return
+		}
+		for i := range due {
+			a.sendAlert(ctx, &due[i])
+		}
+		if len(due) < alertBatchSize {
+			return
+		}
+	}
+}
+
+// sendAlert stores the digest notification and marks its jobs as sent in
+// one transaction, so a job is neither lost nor sent twice when either
+// write fails. On failure the search is retried once its lease expires.
+func (a *savedSearchApp) sendAlert(ctx context.Context, search *domain.SavedSearch) {
+	jobs, err := a.repo.FindUnsentJobs(ctx, search, maxDigestJobs)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "job alert: failed to find new jobs", "saved_search_id", search.ID, "error", err)
+		}
+		// Retried once the lease expires.
+		return
+	}
+	now := time.Now()
+	if len(jobs) > 0 {
+		search.LastAlertAt = &now
+	}
+	search.NextAlertAt = now.Add(search.Frequency.Interval())
+	err = a.tx.InTx(ctx, func(ctx context.Context) error {
+		if len(jobs) > 0 {
+			title, body := a.digest(search, jobs)
+			err := a.notifications.Notify(ctx, search.ApplicantID, domain.NotifyJobAlert, title, body, map[string]string{
+				"saved_search_id": search.ID.String(),
+				"jobs":            strconv.Itoa(len(jobs)),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return a.repo.RecordAlert(ctx, search, jobs)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "job alert: failed to record digest", "saved_search_id", search.ID, "error", err)
+	}
+}
+
+// digest renders the notification for jobs, ending with the unsubscribe link
+func (a *savedSearchApp) digest(search *domain.SavedSearch, jobs []domain.Job) (string, string) {
+	title := fmt.Sprintf("%d new jobs for %q", len(jobs), search.Name)
+	if len(jobs) == 1 {
+		title = fmt.Sprintf("1 new job for %q", search.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "New jobs matching your saved search %q:\n\n", search.Name)
+	for _, job := range jobs {
+		fmt.Fprintf(&b, "- %s", job.Title)
+		if job.Location != "" {
+			fmt.Fprintf(&b, " (%s)", job.Location)
+		}
+		fmt.Fprintf(&b, "\n  %s/jobs/%s\n", a.publicURL, job.ID)
+	}
+	fmt.Fprintf(&b, "\nStop these alerts: %s/alerts/unsubscribe?token=%s\n", a.publicURL, url.QueryEscape(search.UnsubscribeToken))
+	return title, b.String()
+}
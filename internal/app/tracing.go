@@ -0,0 +1,14 @@
+package app
+
+import (
+	"context"
+
+	"github.com/yesetoda/Sera_Ale/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan opens the span covering one app method. Repository and storage
+// calls made with the returned context become its children.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "app."+name)
+}
@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -12,10 +14,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// maxSkills bounds the skills on a profile
+const maxSkills = 30
+
+var ErrAccountSuspended = errors.New("Account suspended")
+
 type UserApp interface {
 	Signup(ctx context.Context, name, email, password, role string) (*domain.User, []string)
 	Login(ctx context.Context, email, password string) (*domain.User, string, []string)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
+	CheckActive(ctx context.Context, id string) error
+	UpdateProfile(ctx context.Context, id string, skills []string, location string) (*domain.User, error)
 }
 
 type userApp struct {
@@ -30,6 +39,8 @@ func NewUserApp(repo repository.UserRepository, jwt service.JWTService, password
 }
 
 func (a *userApp) Signup(ctx context.Context, name, email, password, roleName string) (*domain.User, []string) {
+	ctx, span := startSpan(ctx, "UserApp.Signup")
+	defer span.End()
 	errMsgs := validateSignupInput(name, email, password, roleName)
 	if len(errMsgs) > 0 {
 		return nil, errMsgs
@@ -61,6 +72,8 @@ func (a *userApp) Signup(ctx context.Context, name, email, password, roleName st
 }
 
 func (a *userApp) Login(ctx context.Context, email, password string) (*domain.User, string, []string) {
+	ctx, span := startSpan(ctx, "UserApp.Login")
+	defer span.End()
 	user, err := a.repo.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, "", []string{"User not found"}
@@ -68,6 +81,9 @@ func (a *userApp) Login(ctx context.Context, email, password string) (*domain.Us
 	if err := a.password.ComparePassword(user.Password, password); err != nil {
 		return nil, "", []string{"Incorrect password"}
 	}
+	if user.Suspended {
+		return nil, "", []string{ErrAccountSuspended.Error()}
+	}
 	token, err := a.jwt.GenerateToken(user.ID.String(), user.Role.Name)
 	if err != nil {
 		return nil, "", []string{"Failed to generate token"}
@@ -76,10 +92,74 @@ func (a *userApp) Login(ctx context.Context, email, password string) (*domain.Us
 }
 
 func (a *userApp) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	ctx, span := startSpan(ctx, "UserApp.GetByID")
+	defer span.End()
+	return a.repo.FindByID(ctx, id)
+}
+
+// CheckActive returns ErrAccountSuspended for suspended accounts so tokens
+// issued before a suspension stop working immediately
+func (a *userApp) CheckActive(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "UserApp.CheckActive")
+	defer span.End()
+	user, err := a.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Suspended {
+		return ErrAccountSuspended
+	}
+	return nil
+}
+
+// UpdateProfile replaces the user's skills and location. Skills are trimmed
+// and de-duplicated ignoring case.
+func (a *userApp) UpdateProfile(ctx context.Context, id string, skills []string, location string) (*domain.User, error) {
+	ctx, span := startSpan(ctx, "UserApp.UpdateProfile")
+	defer span.End()
+	var errs []string
+	cleaned := []string{}
+	seen := map[string]bool{}
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		if len(skill) > 50 {
+			errs = append(errs, fmt.Sprintf("Skill %q must be at most 50 characters", skill))
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, skill)
+	}
+	if len(cleaned) > maxSkills {
+		errs = append(errs, fmt.Sprintf("List at most %d skills", maxSkills))
+	}
+	location = strings.TrimSpace(location)
+	if len(location) > 100 {
+		errs = append(errs, "Location must be at most 100 characters")
+	}
+	if len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	if err := a.repo.UpdateProfile(ctx, id, cleaned, location); err != nil {
+		return nil, errors.New("Failed to update profile")
+	}
 	return a.repo.FindByID(ctx, id)
 }
 
 func validateSignupInput(name, email, password, role string) []string {
+	errs := validateCredentials(name, email, password)
+	// Admin accounts can never be self-assigned at signup.
+	role = strings.ToLower(role)
+	if role != domain.RoleCompany && role != domain.RoleApplicant {
+		errs = append(errs, "Role must be either 'company' or 'applicant'")
+	}
+	return errs
+}
+
+func validateCredentials(name, email, password string) []string {
 	errs := []string{}
 	if name == "" || !regexp.MustCompile(`^[A-Za-z ]+$`).MatchString(name) {
 		errs = append(errs, "Name must contain only alphabets and spaces")
@@ -94,9 +174,5 @@ func validateSignupInput(name, email, password, role string) []string {
 		!regexp.MustCompile(`[!@#\$%\^&\*]`).MatchString(password) {
 		errs = append(errs, "Password must be at least 8 characters, include upper, lower, number, and special char")
 	}
-	role = strings.ToLower(role)
-	if role != "company" && role != "applicant" {
-		errs = append(errs, "Role must be either 'company' or 'applicant'")
-	}
 	return errs
 }
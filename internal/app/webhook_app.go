@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"github.com/yesetoda/Sera_Ale/internal/webhook"
+)
+
+// maxWebhooksPerCompany bounds the fan-out of a single event
+const maxWebhooksPerCompany = 10
+
+var (
+	ErrWebhookNotFound  = errors.New("Webhook not found")
+	ErrDeliveryNotFound = errors.New("Delivery not found")
+	ErrTooManyWebhooks  = fmt.Errorf("A company can register at most %d webhooks", maxWebhooksPerCompany)
+)
+
+type WebhookApp interface {
+	EventPublisher
+	CreateWebhook(ctx context.Context, actor policy.Actor, rawURL string, events []string) (*domain.CreatedWebhook, error)
+	ListWebhooks(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.Webhook, domain.PageInfo, error)
+	DeleteWebhook(ctx context.Context, actor policy.Actor, webhookID string) error
+	Deliveries(ctx context.Context, actor policy.Actor, webhookID string, q domain.PageQuery) ([]domain.WebhookDelivery, domain.PageInfo, error)
+	Replay(ctx context.Context, actor policy.Actor, webhookID, deliveryID string) (*domain.WebhookDelivery, error)
+}
+
+type webhookApp struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookApp(repo repository.WebhookRepository) WebhookApp {
+	return &webhookApp{repo: repo}
+}
+
+func (a *webhookApp) CreateWebhook(ctx context.Context, actor policy.Actor, rawURL string, events []string) (*domain.CreatedWebhook, error) {
+	ctx, span := startSpan(ctx, "WebhookApp.CreateWebhook")
+	defer span.End()
+	if err := authorize(actor, policy.WebhookManage, policy.Resource{OwnerID: actor.ID}); err != nil {
+		return nil, err
+	}
+	companyID, err := uuid.Parse(actor.ID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	subscribed, errs := parseWebhookEvents(events)
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, "URL must be an absolute http(s) URL")
+	} else if len(rawURL) > 2000 {
+		errs = append(errs, "URL must be under 2000 characters")
+	}
+	if len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	count, err := a.repo.CountByCompany(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWebhooksPerCompany {
+		return nil, ErrTooManyWebhooks
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	hook := domain.Webhook{
+		ID:        uuid.New(),
+		CompanyID: companyID,
+		URL:       rawURL,
+		Secret:    secret,
+		Events:    subscribed,
+	}
+	if err := a.repo.Create(ctx, &hook); err != nil {
+		return nil, errors.New("Failed to create webhook")
+	}
+	return &domain.CreatedWebhook{Webhook: hook, Secret: secret}, nil
+}
+
+// parseWebhookEvents validates and de-duplicates the requested events
+func parseWebhookEvents(events []string) ([]domain.WebhookEvent, []string) {
+	if len(events) == 0 {
+		return nil, []string{"Subscribe to at least one event"}
+	}
+	var parsed []domain.WebhookEvent
+	var errs []string
+	seen := map[domain.WebhookEvent]bool{}
+	for _, e := range events {
+		event := domain.WebhookEvent(e)
+		if !event.Valid() {
+			errs = append(errs, fmt.Sprintf("Unknown event %q", e))
+			continue
+		}
+		if !seen[event] {
+			seen[event] = true
+			parsed = append(parsed, event)
+		}
+	}
+	return parsed, errs
+}
+
+func (a *webhookApp) ListWebhooks(ctx context.Context, actor policy.Actor, q domain.PageQuery) ([]domain.Webhook, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "WebhookApp.ListWebhooks")
+	defer span.End()
+	if err := authorize(actor, policy.WebhookManage, policy.Resource{OwnerID: actor.ID}); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindByCompany(ctx, actor.ID, q)
+}
+
+func (a *webhookApp) DeleteWebhook(ctx context.Context, actor policy.Actor, webhookID string) error {
+	ctx, span := startSpan(ctx, "WebhookApp.DeleteWebhook")
+	defer span.End()
+	if _, err := a.owned(ctx, actor, webhookID); err != nil {
+		return err
+	}
+	return a.repo.Delete(ctx, webhookID)
+}
+
+func (a *webhookApp) Deliveries(ctx context.Context, actor policy.Actor, webhookID string, q domain.PageQuery) ([]domain.WebhookDelivery, domain.PageInfo, error) {
+	ctx, span := startSpan(ctx, "WebhookApp.Deliveries")
+	defer span.End()
+	if _, err := a.owned(ctx, actor, webhookID); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return a.repo.FindDeliveries(ctx, webhookID, q)
+}
+
+// Replay queues a new delivery with the payload of an earlier one. The
+// original stays in the log untouched.
+func (a *webhookApp) Replay(ctx context.Context, actor policy.Actor, webhookID, deliveryID string) (*domain.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookApp.Replay")
+	defer span.End()
+	hook, err := a.owned(ctx, actor, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := a.repo.FindDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	replay := newDelivery(hook.ID, original.Event, original.Payload)
+	if err := a.repo.CreateDeliveries(ctx, []domain.WebhookDelivery{replay}); err != nil {
+		return nil, errors.New("Failed to queue replay")
+	}
+	return &replay, nil
+}
+
+// owned loads a webhook the actor may manage
+func (a *webhookApp) owned(ctx context.Context, actor policy.Actor, webhookID string) (*domain.Webhook, error) {
+	hook, err := a.repo.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	if err := authorize(actor, policy.WebhookManage, policy.Resource{OwnerID: hook.CompanyID.String()}); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Publish writes one outbox row per subscribed webhook, using the
+// transaction carried by ctx so the rows are only kept if the change that
+// triggered the event is committed. The worker delivers them from there.
+func (a *webhookApp) Publish(ctx context.Context, companyID uuid.UUID, event domain.WebhookEvent, data interface{}) error {
+	ctx, span := startSpan(ctx, "WebhookApp.Publish")
+	defer span.End()
+	hooks, err := a.repo.FindAllByCompany(ctx, companyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to load subscribers", "event", event, "error", err)
+		return err
+	}
+	var deliveries []domain.WebhookDelivery
+	var payload json.RawMessage
+	for _, hook := range hooks {
+		if !hook.Subscribed(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(domain.WebhookPayload{ID: uuid.New(), Event: event, CreatedAt: time.Now().UTC(), Data: data})
+			if err != nil {
+				slog.ErrorContext(ctx, "webhook: failed to encode payload", "event", event, "error", err)
+				return err
+			}
+		}
+		deliveries = append(deliveries, newDelivery(hook.ID, event, payload))
+	}
+	if err := a.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		slog.ErrorContext(ctx, "webhook: failed to queue deliveries", "event", event, "error", err)
+		return err
+	}
+	return nil
+}
+
+func newDelivery(webhookID uuid.UUID, event domain.WebhookEvent, payload json.RawMessage) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        domain.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+}
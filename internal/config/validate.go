@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// minSecretLength is the shortest JWT_SECRET accepted; HS256 keys should carry
+// at least 256 bits
+const minSecretLength = 32
+
+// placeholderSecrets are values copied from examples that must never reach
+// production
+var placeholderSecrets = []string{"your_super_secret_jwt_key", "your_jwt_secret_key_here", "secret", "changeme"}
+
+// ValidationError lists every configuration problem found
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+func (c *Config) validate() []string {
+	var problems []string
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("PORT must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	timeouts := map[string]Duration{
+		"SERVER_READ_TIMEOUT":        c.Server.ReadTimeout,
+		"SERVER_READ_HEADER_TIMEOUT": c.Server.ReadHeaderTimeout,
+		"SERVER_WRITE_TIMEOUT":       c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        c.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":           c.Server.ShutdownTimeout,
+		"READINESS_TIMEOUT":          c.Server.ReadinessTimeout,
+		"WEBHOOK_POLL_INTERVAL":      c.Webhooks.PollInterval,
+		"WEBHOOK_TIMEOUT":            c.Webhooks.Timeout,
+		"WEBHOOK_RETRY_BASE":         c.Webhooks.RetryBase,
+		"MAIL_POLL_INTERVAL":         c.Mail.PollInterval,
+		"ALERT_POLL_INTERVAL":        c.Alerts.PollInterval,
+		"INTERVIEW_REMINDER_LEAD":    c.Interviews.ReminderLead,
+		"INTERVIEW_POLL_INTERVAL":    c.Interviews.PollInterval,
+		"EXPORT_RESUME_LINK_TTL":     c.Exports.ResumeLinkTTL,
+	}
+	for _, key := range slices.Sorted(maps.Keys(timeouts)) {
+		if timeouts[key] <= 0 {
+			problems = append(problems, key+" must be positive")
+		}
+	}
+	if u, err := url.Parse(c.Server.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("PUBLIC_URL must be an absolute http(s) URL, got %q", c.Server.PublicURL))
+	}
+	if c.Server.ShutdownDelay < 0 {
+		problems = append(problems, "SHUTDOWN_DELAY must not be negative")
+	}
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(c.Log.Level)) {
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	if !slices.Contains([]string{"json", "text"}, strings.ToLower(c.Log.Format)) {
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT must be json or text, got %q", c.Log.Format))
+	}
+	if c.Log.SlowQuery < 0 {
+		problems = append(problems, "LOG_SLOW_QUERY must not be negative")
+	}
+	if !slices.Contains([]string{"disabled", "stdout", "otlp"}, c.Tracing.Exporter) {
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be disabled, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SamplePercent < 0 || c.Tracing.SamplePercent > 100 {
+		problems = append(problems, "TRACING_SAMPLE_PERCENT must be between 0 and 100")
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			problems = append(problems, "TRACING_ENDPOINT must be an http(s) URL")
+		}
+	}
+	problems = append(problems, c.Database.validate()...)
+	if c.Cloudinary.URL == "" {
+		problems = append(problems, "CLOUDINARY_URL is required")
+	} else if u, err := url.Parse(c.Cloudinary.URL); err != nil || u.Scheme != "cloudinary" {
+		problems = append(problems, "CLOUDINARY_URL must look like cloudinary://<api_key>:<api_secret>@<cloud_name>")
+	}
+	problems = append(problems, checkSecret(c.JWT.Secret)...)
+	if c.JWT.TTL <= 0 {
+		problems = append(problems, "JWT_TTL must be positive")
+	}
+	if c.Admin.Email != "" && c.Admin.Password == "" {
+		problems = append(problems, "ADMIN_PASSWORD is required when ADMIN_EMAIL is set")
+	}
+	if c.Moderation.NewAccountHours < 0 || c.Moderation.NewAccountMaxJobs < 0 || c.Moderation.ReportThreshold < 0 {
+		problems = append(problems, "MODERATION_* limits must not be negative")
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		problems = append(problems, "WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	}
+	if !slices.Contains([]string{"disabled", "log", "smtp"}, c.Mail.Driver) {
+		problems = append(problems, fmt.Sprintf("MAIL_DRIVER must be disabled, log or smtp, got %q", c.Mail.Driver))
+	}
+	if c.Mail.Driver == "smtp" {
+		if c.Mail.SMTPHost == "" {
+			problems = append(problems, "SMTP_HOST is required when MAIL_DRIVER is smtp")
+		}
+		if c.Mail.SMTPPort < 1 || c.Mail.SMTPPort > 65535 {
+			problems = append(problems, fmt.Sprintf("SMTP_PORT must be between 1 and 65535, got %d", c.Mail.SMTPPort))
+		}
+		if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			problems = append(problems, fmt.Sprintf("MAIL_FROM must be an email address, got %q", c.Mail.From))
+		}
+	}
+	return problems
+}
+
+func (d DatabaseConfig) validate() []string {
+	if d.URL == "" {
+		return []string{"DATABASE_URL is required"}
+	}
+	return nil
+}
+
+func checkSecret(secret string) []string {
+	if secret == "" {
+		return []string{"JWT_SECRET is required"}
+	}
+	var problems []string
+	for _, placeholder := range placeholderSecrets {
+		if strings.EqualFold(secret, placeholder) {
+			problems = append(problems, "JWT_SECRET is still set to an example value")
+			break
+		}
+	}
+	if len(secret) < minSecretLength {
+		problems = append(problems, fmt.Sprintf("JWT_SECRET must be at least %d characters", minSecretLength))
+	}
+	if len(secret) >= minSecretLength && distinctBytes(secret) < 8 {
+		problems = append(problems, "JWT_SECRET is too repetitive")
+	}
+	return problems
+}
+
+func distinctBytes(s string) int {
+	seen := map[byte]bool{}
+	for i := 0; i < len(s); i++ {
+		seen[s[i]] = true
+	}
+	return len(seen)
+}
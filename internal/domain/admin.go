@@ -0,0 +1,8 @@
+package domain
+
+type PlatformStats struct {
+	UsersByRole          map[string]int64            `json:"users_by_role"`
+	SuspendedUsers       int64                       `json:"suspended_users"`
+	JobsByStatus         map[JobStatus]int64         `json:"jobs_by_status"`
+	ApplicationsByStatus map[ApplicationStatus]int64 `json:"applications_by_status"`
+}
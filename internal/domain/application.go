@@ -14,14 +14,29 @@ const (
 	StatusInterview ApplicationStatus = "Interview"
 	StatusRejected  ApplicationStatus = "Rejected"
 	StatusHired     ApplicationStatus = "Hired"
+	// StatusWithdrawn is set by the applicant and hides the application's
+	// message thread.
+	StatusWithdrawn ApplicationStatus = "Withdrawn"
 )
 
 type Application struct {
 	ID          uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	ApplicantID uuid.UUID         `json:"applicant_id"`
-	JobID       uuid.UUID         `json:"job_id"`
+	ApplicantID uuid.UUID         `gorm:"uniqueIndex:idx_applications_applicant_job" json:"applicant_id"`
+	JobID       uuid.UUID         `gorm:"uniqueIndex:idx_applications_applicant_job" json:"job_id"`
 	ResumeLink  string            `json:"resume_link"`
 	CoverLetter string            `json:"cover_letter"`
 	Status      ApplicationStatus `json:"status"`
-	AppliedAt   time.Time         `json:"applied_at"`
+	AppliedAt   time.Time         `gorm:"autoCreateTime" json:"applied_at"`
+}
+
+// ApplicantRow is an application together with the applicant's name and
+// email, as exported for a job
+type ApplicantRow struct {
+	ApplicationID uuid.UUID
+	Name          string
+	Email         string
+	Status        ApplicationStatus
+	AppliedAt     time.Time
+	CoverLetter   string
+	ResumeLink    string
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bookmark is a job an applicant saved to come back to later
+type Bookmark struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ApplicantID uuid.UUID `gorm:"type:uuid;not null" json:"applicant_id"`
+	JobID       uuid.UUID `gorm:"type:uuid;not null" json:"job_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SavedJob is a bookmarked job as listed to the applicant who saved it
+type SavedJob struct {
+	Job     *Job      `json:"job"`
+	SavedAt time.Time `json:"saved_at"`
+	// Open is false once the job is held or removed
+	Open          bool       `json:"open"`
+	Applied       bool       `json:"applied"`
+	ApplicationID *uuid.UUID `json:"application_id,omitempty"`
+}
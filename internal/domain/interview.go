@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InterviewStatus string
+
+const (
+	// InterviewProposed waits for the applicant to pick one of the slots.
+	InterviewProposed  InterviewStatus = "proposed"
+	InterviewScheduled InterviewStatus = "scheduled"
+	InterviewCancelled InterviewStatus = "cancelled"
+)
+
+// InterviewSlot is one time the company offers for an interview
+type InterviewSlot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Interview is attached to an application. The company proposes slots, the
+// applicant picks one, and either side can cancel. Rescheduling replaces the
+// slots and sends the interview back to proposed.
+type Interview struct {
+	ID            uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ApplicationID uuid.UUID       `gorm:"type:uuid;not null" json:"application_id"`
+	JobID         uuid.UUID       `gorm:"type:uuid;not null" json:"job_id"`
+	CompanyID     uuid.UUID       `gorm:"type:uuid;not null" json:"company_id"`
+	ApplicantID   uuid.UUID       `gorm:"type:uuid;not null" json:"applicant_id"`
+	Status        InterviewStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Slots         []InterviewSlot `gorm:"type:jsonb;serializer:json" json:"slots"`
+	// StartsAt and EndsAt are the chosen slot, set once scheduled.
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Location     string     `json:"location"`
+	MeetingURL   string     `json:"meeting_url"`
+	Interviewers []string   `gorm:"type:jsonb;serializer:json" json:"interviewers"`
+	Notes        string     `json:"notes"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+	// Sequence counts changes for calendar clients, which only apply an
+	// updated .ics with a higher sequence.
+	Sequence       int        `gorm:"not null" json:"-"`
+	ReminderSentAt *time.Time `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+type JobStatus string
+
+const (
+	JobStatusPublished JobStatus = "published"
+	JobStatusHeld      JobStatus = "held"
+	JobStatusRemoved   JobStatus = "removed"
+)
+
 type Job struct {
 	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	Title       string    `json:"title"`
@@ -13,4 +21,11 @@ type Job struct {
 	Location    string    `json:"location"`
 	CreatedBy   uuid.UUID `json:"created_by"`
 	CreatedAt   time.Time `json:"created_at"`
+	Status      JobStatus `gorm:"type:varchar(20);not null;default:published" json:"status"`
+	HoldReason  string    `json:"hold_reason,omitempty"`
+}
+
+// Visible reports whether the job may be shown to the public
+func (j *Job) Visible() bool {
+	return j.Status == JobStatusPublished
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "github.com/google/uuid"
+
+// JobImportRow is the outcome for one job of a bulk import. Row counts the
+// jobs in the upload from 1, not counting a CSV header.
+type JobImportRow struct {
+	Row    int        `json:"row"`
+	Title  string     `json:"title"`
+	Errors []string   `json:"errors,omitempty"`
+	JobID  *uuid.UUID `json:"job_id,omitempty"`
+	Status JobStatus  `json:"status,omitempty"`
+}
+
+// JobImportResult reports a bulk import. In a dry run nothing is stored and
+// JobID stays empty.
+type JobImportResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Total    int            `json:"total"`
+	Imported int            `json:"imported"`
+	Invalid  int            `json:"invalid"`
+	Rows     []JobImportRow `json:"rows"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is one post in the thread between an applicant and the company
+// an application was sent to. RecipientID is the other side of the thread;
+// ReadAt is set once they have read it.
+type Message struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ApplicationID  uuid.UUID  `gorm:"type:uuid;not null" json:"application_id"`
+	SenderID       uuid.UUID  `gorm:"type:uuid;not null" json:"sender_id"`
+	RecipientID    uuid.UUID  `gorm:"type:uuid;not null" json:"recipient_id"`
+	Body           string     `json:"body"`
+	AttachmentURL  string     `json:"attachment_url,omitempty"`
+	AttachmentName string     `json:"attachment_name,omitempty"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// UnreadCount is the number of unread messages in one application's thread
+type UnreadCount struct {
+	ApplicationID uuid.UUID `json:"application_id"`
+	Unread        int64     `json:"unread"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	// NotifyApplicationReceived tells a company about a new application to
+	// one of its jobs.
+	NotifyApplicationReceived NotificationType = "application_received"
+	// NotifyApplicationStatus tells an applicant their application moved to
+	// another status, or a company that an application was withdrawn.
+	NotifyApplicationStatus NotificationType = "application_status_changed"
+	// NotifyJobAlert is a digest of new jobs matching a saved search.
+	NotifyJobAlert NotificationType = "job_alert"
+	// NotifyInterview tells either side an interview was proposed, booked,
+	// rescheduled or cancelled.
+	NotifyInterview NotificationType = "interview_updated"
+	// NotifyInterviewReminder is sent to both sides ahead of an interview.
+	NotifyInterviewReminder NotificationType = "interview_reminder"
+	// NotifyMessage tells either side of an application about a new message.
+	NotifyMessage NotificationType = "message_received"
+)
+
+// NotificationTypes lists every type a user can set preferences for
+var NotificationTypes = []NotificationType{
+	NotifyApplicationReceived, NotifyApplicationStatus, NotifyJobAlert, NotifyInterview, NotifyInterviewReminder,
+	NotifyMessage,
+}
+
+func (t NotificationType) Valid() bool {
+	switch t {
+	case NotifyApplicationReceived, NotifyApplicationStatus, NotifyJobAlert, NotifyInterview, NotifyInterviewReminder,
+		NotifyMessage:
+		return true
+	}
+	return false
+}
+
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	EmailFailed  EmailStatus = "failed"
+	EmailSkipped EmailStatus = "skipped"
+)
+
+// Notification is a message for one user. It is shown in the in-app inbox
+// when InInbox is set and doubles as the outbox row for its email, which is
+// sent in the background while EmailStatus is pending.
+type Notification struct {
+	ID                 uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID             uuid.UUID         `gorm:"type:uuid;not null" json:"user_id"`
+	Type               NotificationType  `json:"type"`
+	Title              string            `json:"title"`
+	Body               string            `json:"body"`
+	Data               map[string]string `gorm:"type:jsonb;serializer:json" json:"data"`
+	InInbox            bool              `gorm:"not null" json:"-"`
+	ReadAt             *time.Time        `json:"read_at"`
+	EmailStatus        EmailStatus       `gorm:"type:varchar(20);not null;default:skipped" json:"-"`
+	EmailAttempts      int               `gorm:"not null;default:0" json:"-"`
+	EmailNextAttemptAt time.Time         `json:"-"`
+	CreatedAt          time.Time         `json:"created_at"`
+}
+
+// NotificationPreference says where a user wants notifications of one type.
+// Types without a stored preference go to both the inbox and email.
+type NotificationPreference struct {
+	UserID uuid.UUID        `gorm:"type:uuid;primaryKey" json:"-"`
+	Type   NotificationType `gorm:"type:varchar(50);primaryKey" json:"type"`
+	InApp  bool             `gorm:"not null" json:"in_app"`
+	Email  bool             `gorm:"not null" json:"email"`
+}
+
+// DefaultNotificationPreference applies until the user stores their own for t
+func DefaultNotificationPreference(userID uuid.UUID, t NotificationType) NotificationPreference {
+	return NotificationPreference{UserID: userID, Type: t, InApp: true, Email: true}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+// PageQuery selects a slice of a listing. A non-empty Cursor switches to
+// keyset pagination and Page is ignored; otherwise offset paging is used.
+type PageQuery struct {
+	Page   int
+	Size   int
+	Cursor string
+}
+
+// Normalize fills in defaults and caps the page size at MaxPageSize
+func (q PageQuery) Normalize() PageQuery {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Size < 1 {
+		q.Size = DefaultPageSize
+	}
+	if q.Size > MaxPageSize {
+		q.Size = MaxPageSize
+	}
+	return q
+}
+
+// Offset is the number of rows skipped in offset mode
+func (q PageQuery) Offset() int {
+	return (q.Page - 1) * q.Size
+}
+
+// PageInfo describes the position of a returned page within the listing
+type PageInfo struct {
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+// Cursor is the decoded form of an opaque pagination cursor. Listings are
+// ordered newest first on (created_at, id); Backward cursors walk towards
+// newer rows.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
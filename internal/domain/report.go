@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportReason string
+
+const (
+	ReportScam           ReportReason = "scam"
+	ReportDiscriminatory ReportReason = "discriminatory"
+	ReportSpam           ReportReason = "spam"
+	ReportOther          ReportReason = "other"
+)
+
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReportScam, ReportDiscriminatory, ReportSpam, ReportOther:
+		return true
+	}
+	return false
+}
+
+// JobReport is an applicant's complaint about a job. Each applicant can
+// report a given job once.
+type JobReport struct {
+	ID         uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	JobID      uuid.UUID    `gorm:"uniqueIndex:idx_job_reports_job_reporter" json:"job_id"`
+	ReporterID uuid.UUID    `gorm:"uniqueIndex:idx_job_reports_job_reporter" json:"reporter_id"`
+	Reason     ReportReason `json:"reason"`
+	Details    string       `json:"details"`
+	Resolved   bool         `gorm:"not null;default:false" json:"resolved"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// ModerationItem is an entry in the admin moderation queue: a held job or a
+// job with unresolved reports.
+type ModerationItem struct {
+	Job     Job         `json:"job"`
+	Reports []JobReport `json:"reports"`
+}
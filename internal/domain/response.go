@@ -11,8 +11,10 @@ type PaginatedResponse struct {
 	Success    bool        `json:"success"`
 	Message    string      `json:"message"`
 	Object     interface{} `json:"object"`
-	PageNumber int         `json:"page_number"`
+	PageNumber int         `json:"page_number,omitempty"`
 	PageSize   int         `json:"page_size"`
 	TotalSize  int         `json:"total_size"`
+	NextCursor string      `json:"next_cursor"`
+	PrevCursor string      `json:"prev_cursor"`
 	Errors     []string    `json:"errors"`
 }
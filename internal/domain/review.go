@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationNote is a private note a company keeps on an application.
+// Applicants never see notes, ratings or tags.
+type ApplicationNote struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ApplicationID uuid.UUID `gorm:"type:uuid;not null" json:"application_id"`
+	AuthorID      uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ApplicationRating is one reviewer's 1-5 score of an application
+type ApplicationRating struct {
+	ApplicationID uuid.UUID `gorm:"type:uuid;primaryKey" json:"application_id"`
+	ReviewerID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"reviewer_id"`
+	Rating        int       `json:"rating"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ApplicationTag is a free-form label on an application, stored lowercased
+type ApplicationTag struct {
+	ApplicationID uuid.UUID `gorm:"type:uuid;primaryKey" json:"application_id"`
+	Tag           string    `gorm:"primaryKey" json:"tag"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// RatingSummary is the average of an application's ratings. Average is nil
+// while nobody has rated it.
+type RatingSummary struct {
+	Average *float64 `json:"average_rating"`
+	Count   int      `json:"rating_count"`
+}
+
+// ApplicationReview is everything a company noted about one application
+type ApplicationReview struct {
+	ApplicationID uuid.UUID           `json:"application_id"`
+	Notes         []ApplicationNote   `json:"notes"`
+	Ratings       []ApplicationRating `json:"ratings"`
+	Tags          []string            `json:"tags"`
+	RatingSummary
+}
+
+// ReviewedApplication is an application as listed to the company that owns
+// the job, with its tags and average rating
+type ReviewedApplication struct {
+	Application
+	Tags []string `json:"tags"`
+	RatingSummary
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertFrequency is how often a saved search emails its new matches
+type AlertFrequency string
+
+const (
+	AlertDaily  AlertFrequency = "daily"
+	AlertWeekly AlertFrequency = "weekly"
+	AlertOff    AlertFrequency = "off"
+)
+
+func (f AlertFrequency) Valid() bool {
+	switch f {
+	case AlertDaily, AlertWeekly, AlertOff:
+		return true
+	}
+	return false
+}
+
+// Interval is the time between two digests; zero when alerts are off
+func (f AlertFrequency) Interval() time.Duration {
+	switch f {
+	case AlertDaily:
+		return 24 * time.Hour
+	case AlertWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// SavedSearch is a named set of /applicant/jobs filters. Unless its
+// frequency is off, new published jobs matching it are sent to the applicant
+// as a digest when NextAlertAt is reached.
+type SavedSearch struct {
+	ID               uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ApplicantID      uuid.UUID      `gorm:"type:uuid;not null" json:"applicant_id"`
+	Name             string         `json:"name"`
+	Title            string         `json:"title"`
+	Location         string         `json:"location"`
+	CompanyName      string         `json:"company_name"`
+	Frequency        AlertFrequency `gorm:"type:varchar(10);not null" json:"frequency"`
+	UnsubscribeToken string         `json:"-"`
+	NextAlertAt      time.Time      `json:"next_alert_at"`
+	LastAlertAt      *time.Time     `json:"last_alert_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+// Filters returns the search in the form accepted by JobRepository.Search
+func (s *SavedSearch) Filters() map[string]interface{} {
+	filters := map[string]interface{}{}
+	if s.Title != "" {
+		filters["title"] = s.Title
+	}
+	if s.Location != "" {
+		filters["location"] = s.Location
+	}
+	if s.CompanyName != "" {
+		filters["company_name"] = s.CompanyName
+	}
+	return filters
+}
+
+// SavedSearchJob records that a job was already sent in one of the search's
+// digests, so it is never sent twice
+type SavedSearchJob struct {
+	SavedSearchID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	JobID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SentAt        time.Time
+}
@@ -1,19 +1,33 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
+const (
+	RoleApplicant = "applicant"
+	RoleCompany   = "company"
+	RoleAdmin     = "admin"
+)
+
 type Role struct {
 	ID   uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	Name string    `gorm:"unique;not null" json:"name"`
 }
 
 type User struct {
-	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name     string    `json:"name"`
-	Email    string    `json:"email"`
-	Password string    `json:"-"`
-	RoleID   uuid.UUID `gorm:"type:uuid;not null" json:"role_id"`
-	Role     Role      `gorm:"foreignKey:RoleID" json:"role"`
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Password  string    `json:"-"`
+	RoleID    uuid.UUID `gorm:"type:uuid;not null" json:"role_id"`
+	Role      Role      `gorm:"foreignKey:RoleID" json:"role"`
+	Suspended bool      `gorm:"not null;default:false" json:"suspended"`
+	// Skills and Location make up an applicant's profile; they are used to
+	// recommend jobs.
+	Skills    []string  `gorm:"type:jsonb;serializer:json;default:'[]'" json:"skills"`
+	Location  string    `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
 }
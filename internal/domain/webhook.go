@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEvent string
+
+const (
+	EventApplicationCreated       WebhookEvent = "application.created"
+	EventApplicationStatusChanged WebhookEvent = "application.status_changed"
+	EventJobClosed                WebhookEvent = "job.closed"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to
+var WebhookEvents = []WebhookEvent{EventApplicationCreated, EventApplicationStatusChanged, EventJobClosed}
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case EventApplicationCreated, EventApplicationStatusChanged, EventJobClosed:
+		return true
+	}
+	return false
+}
+
+// Webhook is an endpoint a company registered to receive events about its
+// jobs. Secret signs every delivery and is only shown when it is created.
+type Webhook struct {
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CompanyID uuid.UUID      `gorm:"type:uuid;not null" json:"company_id"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"-"`
+	Events    []WebhookEvent `gorm:"type:jsonb;serializer:json" json:"events"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Subscribed reports whether the webhook wants event
+func (w *Webhook) Subscribed(event WebhookEvent) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedWebhook is returned once, when the webhook is registered
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookPayload is the JSON body POSTed to a webhook. ID identifies the
+// event and stays the same across retries and replays, so receivers can use
+// it to drop duplicates.
+type WebhookPayload struct {
+	ID        uuid.UUID    `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      interface{}  `json:"data"`
+}
+
+// ApplicationEventData is the payload data of application events.
+// PreviousStatus is only set for application.status_changed.
+type ApplicationEventData struct {
+	Application    *Application      `json:"application"`
+	Job            *Job              `json:"job"`
+	PreviousStatus ApplicationStatus `json:"previous_status,omitempty"`
+}
+
+// JobClosedData is the payload data of job.closed. Reason is deleted when
+// the company deleted the job and removed when an admin took it down.
+type JobClosedData struct {
+	Job    *Job   `json:"job"`
+	Reason string `json:"reason"`
+}
+
+// WebhookDelivery is a row in the webhook outbox. Pending deliveries are
+// picked up by the dispatcher once NextAttemptAt has passed; a delivery is
+// failed for good after the configured number of attempts.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	WebhookID      uuid.UUID       `gorm:"type:uuid;not null" json:"webhook_id"`
+	Webhook        *Webhook        `gorm:"foreignKey:WebhookID" json:"-"`
+	Event          WebhookEvent    `json:"event"`
+	Payload        json.RawMessage `gorm:"type:jsonb;serializer:json" json:"payload"`
+	Status         DeliveryStatus  `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	Attempts       int             `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,175 @@
+// Package feed renders lists of job postings as RSS 2.0 and Atom 1.0 feeds
+// and single postings as schema.org JobPosting JSON-LD, for aggregators and
+// search engines.
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Feed is a list of postings, newest first. Self is the URL of the feed
+// without an extension; each format appends its own (".rss" or ".atom").
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	Self        string
+	Items       []Item
+}
+
+// Item is one posting in a feed
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Author    string
+	Published time.Time
+}
+
+// Updated is when the newest item was published, or now for an empty feed
+func (f Feed) Updated() time.Time {
+	var updated time.Time
+	for _, item := range f.Items {
+		if item.Published.After(updated) {
+			updated = item.Published
+		}
+	}
+	if updated.IsZero() {
+		return time.Now()
+	}
+	return updated
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders f as an RSS 2.0 document
+func RSS(f Feed) ([]byte, error) {
+	doc := rssDoc{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			Self:          atomLink{Href: f.Self + ".rss", Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: f.Updated().UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			Description: item.Summary,
+			Creator:     item.Author,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return encode(doc)
+}
+
+type atomDoc struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Summary   atomText    `xml:"summary"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom renders f as an Atom 1.0 document. Entries without an author fall
+// back to the feed's title as its author.
+func Atom(f Feed) ([]byte, error) {
+	doc := atomDoc{
+		ID:      f.Self + ".atom",
+		Title:   f.Title,
+		Updated: f.Updated().UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Self + ".atom", Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate"},
+		},
+		Author: atomPerson{Name: f.Title},
+	}
+	for _, item := range f.Items {
+		published := item.Published.UTC().Format(time.RFC3339)
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Published: published,
+			Updated:   published,
+			Summary:   atomText{Type: "text", Value: item.Summary},
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return encode(doc)
+}
+
+func encode(doc any) ([]byte, error) {
+	out, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
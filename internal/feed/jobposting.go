@@ -0,0 +1,82 @@
+package feed
+
+import (
+	"strings"
+	"time"
+)
+
+// JobPosting is a schema.org JobPosting, the structured data search engines
+// such as Google for Jobs read. It marshals to JSON-LD.
+type JobPosting struct {
+	Context            string        `json:"@context"`
+	Type               string        `json:"@type"`
+	Identifier         PropertyValue `json:"identifier"`
+	Title              string        `json:"title"`
+	Description        string        `json:"description"`
+	DatePosted         string        `json:"datePosted"`
+	URL                string        `json:"url"`
+	DirectApply        bool          `json:"directApply"`
+	HiringOrganization Organization  `json:"hiringOrganization"`
+	JobLocation        *Place        `json:"jobLocation,omitempty"`
+	JobLocationType    string        `json:"jobLocationType,omitempty"`
+	ApplicantLocation  *Country      `json:"applicantLocationRequirements,omitempty"`
+}
+
+type PropertyValue struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Organization struct {
+	Type   string `json:"@type"`
+	Name   string `json:"name"`
+	SameAs string `json:"sameAs,omitempty"`
+}
+
+type Place struct {
+	Type    string        `json:"@type"`
+	Address PostalAddress `json:"address"`
+}
+
+type PostalAddress struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality"`
+}
+
+type Country struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// NewJobPosting fills in a JobPosting. A location of "remote" marks a
+// telecommute job open to applicants in remoteCountry; any other location is
+// used as the job's locality. Search engines reject postings with neither,
+// so ok is false for jobs without a location, and for remote jobs when
+// remoteCountry is empty.
+func NewJobPosting(id, title, description, location, remoteCountry, url string, posted time.Time, source, company string) (p JobPosting, ok bool) {
+	p = JobPosting{
+		Context:            "https://schema.org/",
+		Type:               "JobPosting",
+		Identifier:         PropertyValue{Type: "PropertyValue", Name: source, Value: id},
+		Title:              title,
+		Description:        description,
+		DatePosted:         posted.UTC().Format(time.RFC3339),
+		URL:                url,
+		DirectApply:        true,
+		HiringOrganization: Organization{Type: "Organization", Name: company},
+	}
+	switch location = strings.TrimSpace(location); {
+	case location == "":
+		return p, false
+	case strings.EqualFold(location, "remote"):
+		if remoteCountry == "" {
+			return p, false
+		}
+		p.JobLocationType = "TELECOMMUTE"
+		p.ApplicantLocation = &Country{Type: "Country", Name: remoteCountry}
+	default:
+		p.JobLocation = &Place{Type: "Place", Address: PostalAddress{Type: "PostalAddress", AddressLocality: location}}
+	}
+	return p, true
+}
@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
+)
+
+// actorFrom builds the policy actor from the claims AuthMiddleware stored in
+// the context. Public routes yield the anonymous zero Actor.
+func actorFrom(c *gin.Context) policy.Actor {
+	return policy.Actor{ID: c.GetString("user_id"), Role: c.GetString("role")}
+}
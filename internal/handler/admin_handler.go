@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type AdminHandler struct {
+	App app.AdminApp
+}
+
+func NewAdminHandler(app app.AdminApp) *AdminHandler {
+	return &AdminHandler{App: app}
+}
+
+// ListUsers godoc
+// @Summary List and search users
+// @Description Admin lists users, optionally filtered by name/email, role or suspension state (requires Bearer token)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param q query string false "Matches name or email"
+// @Param role query string false "Role name (applicant, company or admin)"
+// @Param suspended query bool false "Suspension state"
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Failure 403 {object} map[string]interface{} "Forbidden"
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	filters := map[string]interface{}{}
+	if query := c.Query("q"); query != "" {
+		filters["q"] = query
+	}
+	if role := c.Query("role"); role != "" {
+		filters["role"] = role
+	}
+	if suspended := c.Query("suspended"); suspended != "" {
+		value, err := strconv.ParseBool(suspended)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: "suspended must be true or false"})
+			return
+		}
+		filters["suspended"] = value
+	}
+	q := pageQuery(c)
+	users, info, err := h.App.ListUsers(c.Request.Context(), actorFrom(c), filters, q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, app.ErrUnauthorized) {
+		c.JSON(http.StatusForbidden, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.PaginatedResponse{Success: false, Message: "Failed to fetch users"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Users found", users, q, info))
+}
+
+// SuspendUser godoc
+// @Summary Suspend user
+// @Description Admin suspends an account; its tokens stop working immediately (requires Bearer token)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	user, err := h.App.SuspendUser(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "User suspended", Object: user})
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate user
+// @Description Admin lifts a suspension (requires Bearer token)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/reactivate [post]
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	user, err := h.App.ReactivateUser(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "User reactivated", Object: user})
+}
+
+// TakeDownJob godoc
+// @Summary Take down job
+// @Description Admin hides a job from all public listings (requires Bearer token)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /admin/jobs/{id}/takedown [post]
+func (h *AdminHandler) TakeDownJob(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	job, err := h.App.TakeDownJob(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(adminErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job taken down", Object: job})
+}
+
+// Stats godoc
+// @Summary Platform statistics
+// @Description Admin views user, job and application counts (requires Bearer token)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /admin/stats [get]
+func (h *AdminHandler) Stats(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	stats, err := h.App.Stats(c.Request.Context(), actorFrom(c))
+	if errors.Is(err, app.ErrUnauthorized) {
+		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to load statistics"})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Statistics loaded", Object: stats})
+}
+
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrUserNotFound), errors.Is(err, app.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrCannotSuspendAdmin):
+		return http.StatusBadRequest
+	case errors.Is(err, app.ErrJobAlreadyRemoved):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
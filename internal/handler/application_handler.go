@@ -1,12 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
 )
 
 type ApplicationHandler struct {
@@ -27,7 +28,7 @@ type applyRequest struct {
 // OpenAPI3: tags: [Applications]
 // OpenAPI3: security: BearerAuth
 // OpenAPI3: requestBody: multipart/form-data (job_id, cover_letter, resume)
-// OpenAPI3: responses: 200=BaseResponse, 400=BaseResponse
+// OpenAPI3: responses: 200=BaseResponse, 400=BaseResponse, 404=BaseResponse, 409=BaseResponse
 // @Security BearerAuth
 // Requires Bearer token
 func (h *ApplicationHandler) Apply(c *gin.Context) {
@@ -36,7 +37,6 @@ func (h *ApplicationHandler) Apply(c *gin.Context) {
 		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
 		return
 	}
-	applicantID := c.GetString("user_id")
 	var req applyRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid input"})
@@ -48,9 +48,21 @@ func (h *ApplicationHandler) Apply(c *gin.Context) {
 		return
 	}
 	defer file.Close()
-	application, errs := h.App.Apply(c.Request.Context(), applicantID, req.JobID, req.CoverLetter, file)
-	if len(errs) > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Application failed", "errors": errs})
+	application, err := h.App.Apply(c.Request.Context(), actorFrom(c), req.JobID, req.CoverLetter, file)
+	if err != nil {
+		var verr app.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Application failed", "errors": []string(verr)})
+		case errors.Is(err, app.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "Application failed", "errors": []string{err.Error()}})
+		case errors.Is(err, app.ErrAlreadyApplied):
+			c.JSON(http.StatusConflict, gin.H{"success": false, "message": "Application failed", "errors": []string{err.Error()}})
+		case errors.Is(err, app.ErrJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Application failed", "errors": []string{err.Error()}})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Application failed", "errors": []string{err.Error()}})
+		}
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Application submitted", "object": application})
@@ -63,8 +75,10 @@ func (h *ApplicationHandler) Apply(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
-// @Param size query int false "Page size"
-// @Success 200 {object} map[string]interface{} "List of applications"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse "List of applications"
+// @Failure 400 {object} domain.PaginatedResponse "Invalid cursor"
 // @Security BearerAuth
 // @Router /applicant/applications [get]
 // Requires Bearer token
@@ -74,27 +88,36 @@ func (h *ApplicationHandler) TrackApplications(c *gin.Context) {
 		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
 		return
 	}
-	applicantID := c.GetString("user_id")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
-	apps, total, err := h.App.TrackApplications(c.Request.Context(), applicantID, page, size)
+	q := pageQuery(c)
+	apps, info, err := h.App.TrackApplications(c.Request.Context(), actorFrom(c), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, app.ErrUnauthorized) {
+		c.JSON(http.StatusForbidden, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch applications"})
+		c.JSON(http.StatusInternalServerError, domain.PaginatedResponse{Success: false, Message: "Failed to fetch applications"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Applications found", "object": apps, "page_number": page, "page_size": size, "total_size": total})
+	c.JSON(http.StatusOK, paginatedResponse("Applications found", apps, q, info))
 }
 
 // GetApplicationsForJob godoc
 // @Summary View applications for a job
-// @Description Company views applications for their job. Requires Bearer token in Authorization header.
+// @Description Company views applications for their job, with each application's tags and average rating. Requires Bearer token in Authorization header.
 // @Tags Applications
 // @Accept json
 // @Produce json
 // @Param job_id query string true "Job ID"
+// @Param tag query []string false "Only applications with every given tag (repeat for several)" collectionFormat(multi)
 // @Param page query int false "Page number"
-// @Param size query int false "Page size"
-// @Success 200 {object} map[string]interface{} "List of applications"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse "List of applications"
+// @Failure 400 {object} domain.PaginatedResponse "Invalid cursor or tag"
 // @Failure 403 {object} map[string]interface{} "Unauthorized or not job owner"
 // @Security BearerAuth
 // @Router /company/applications/job [get]
@@ -105,16 +128,23 @@ func (h *ApplicationHandler) GetApplicationsForJob(c *gin.Context) {
 		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
 		return
 	}
-	companyID := c.GetString("user_id")
 	jobID := c.Query("job_id")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
-	apps, total, err := h.App.GetApplicationsForJob(c.Request.Context(), jobID, companyID, page, size)
+	q := pageQuery(c)
+	apps, info, err := h.App.GetApplicationsForJob(c.Request.Context(), actorFrom(c), jobID, c.QueryArray("tag"), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	var verr app.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid tag filter", Errors: verr})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Applications found", "object": apps, "page_number": page, "page_size": size, "total_size": total})
+	c.JSON(http.StatusOK, paginatedResponse("Applications found", apps, q, info))
 }
 
 type updateStatusRequest struct {
@@ -141,14 +171,13 @@ func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
 		return
 	}
-	companyID := c.GetString("user_id")
 	id := c.Param("id")
 	var req updateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid input"})
 		return
 	}
-	app, err := h.App.UpdateStatus(c.Request.Context(), id, companyID, req.Status)
+	app, err := h.App.UpdateStatus(c.Request.Context(), actorFrom(c), id, req.Status)
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": err.Error()})
 		return
@@ -156,4 +185,40 @@ func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Status updated", "object": app})
 }
 
+// Withdraw godoc
+// @Summary Withdraw application
+// @Description Applicant withdraws their application; the company is notified and the application's message thread is hidden (requires Bearer token)
+// @Tags Applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/applications/{id}/withdraw [post]
+func (h *ApplicationHandler) Withdraw(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	application, err := h.App.Withdraw(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, app.ErrApplicationNotFound), errors.Is(err, app.ErrJobNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, app.ErrUnauthorized):
+			status = http.StatusForbidden
+		case errors.Is(err, app.ErrAlreadyWithdrawn):
+			status = http.StatusConflict
+		}
+		c.JSON(status, domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Application withdrawn", Object: application})
+}
+
 // ... existing code ...
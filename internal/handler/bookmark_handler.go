@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type BookmarkHandler struct {
+	App app.BookmarkApp
+}
+
+func NewBookmarkHandler(app app.BookmarkApp) *BookmarkHandler {
+	return &BookmarkHandler{App: app}
+}
+
+// SaveJob godoc
+// @Summary Save job
+// @Description Applicant bookmarks a job to apply to later; saving it again has no effect (requires Bearer token)
+// @Tags Saved Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/jobs/{id}/save [post]
+func (h *BookmarkHandler) SaveJob(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	bookmark, err := h.App.SaveJob(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(bookmarkErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job saved", Object: bookmark})
+}
+
+// UnsaveJob godoc
+// @Summary Unsave job
+// @Description Applicant removes a job from their saved jobs (requires Bearer token)
+// @Tags Saved Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/jobs/{id}/save [delete]
+func (h *BookmarkHandler) UnsaveJob(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	if err := h.App.UnsaveJob(c.Request.Context(), actorFrom(c), c.Param("id")); err != nil {
+		c.JSON(bookmarkErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job removed from saved jobs"})
+}
+
+// SavedJobs godoc
+// @Summary List saved jobs
+// @Description Applicant lists their saved jobs, newest first, with whether each job is still open and whether they already applied (requires Bearer token)
+// @Tags Saved Jobs
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /applicant/saved-jobs [get]
+func (h *BookmarkHandler) SavedJobs(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	saved, info, err := h.App.SavedJobs(c.Request.Context(), actorFrom(c), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(bookmarkErrorStatus(err), domain.PaginatedResponse{Success: false, Message: "Failed to fetch saved jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Saved jobs found", saved, q, info))
+}
+
+func bookmarkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
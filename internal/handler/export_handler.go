@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/spreadsheet"
+)
+
+// exportFlushRows is how many rows are written between flushes to the client
+const exportFlushRows = 200
+
+type ExportHandler struct {
+	App app.ExportApp
+}
+
+func NewExportHandler(app app.ExportApp) *ExportHandler {
+	return &ExportHandler{App: app}
+}
+
+// ExportApplicants godoc
+// @Summary Export applicants
+// @Description Company downloads every application for its job as CSV or XLSX: applicant name, email, status, applied_at, cover letter and a signed resume link that works without logging in until it expires. The file is streamed as it is read from the database (requires Bearer token)
+// @Tags Applications
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path string true "Job ID"
+// @Param format query string false "csv (default) or xlsx"
+// @Success 200 {file} file "Applicant export"
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/jobs/{id}/applications/export [get]
+func (h *ExportHandler) ExportApplicants(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	format, ok := spreadsheet.Lookup(c.DefaultQuery("format", spreadsheet.CSV.Name))
+	if !ok {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "format must be csv or xlsx"})
+		return
+	}
+	export, err := h.App.ExportApplicants(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(exportErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	// Large exports can take longer than the server's write timeout.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	filename := fmt.Sprintf("applicants-%s%s", export.Job.ID, format.Extension)
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	w, err := format.New(c.Writer)
+	if err == nil {
+		err = w.WriteRow(export.Header)
+	}
+	n := 0
+	for row, rerr := range export.Rows {
+		if err != nil {
+			break
+		}
+		if err = rerr; err != nil {
+			break
+		}
+		if err = w.WriteRow(row); err != nil {
+			break
+		}
+		if n++; n%exportFlushRows == 0 {
+			c.Writer.Flush()
+		}
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		// Headers are already sent; all that can be done is cut the file
+		// short so the client sees an incomplete download.
+		slog.ErrorContext(c.Request.Context(), "applicant export failed", "job_id", export.Job.ID, "rows", n, "error", err)
+		c.Abort()
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// OpenResume godoc
+// @Summary Open resume from an export
+// @Description Redirects a signed resume link from an applicant export to the stored resume (public, but the link expires)
+// @Tags Applications
+// @Param id path string true "Application ID"
+// @Param expires query int true "Expiry as a unix time"
+// @Param signature query string true "Link signature"
+// @Success 302 "Redirect to the resume"
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Router /resumes/{id} [get]
+func (h *ExportHandler) OpenResume(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: app.ErrInvalidLink.Error()})
+		return
+	}
+	url, err := h.App.ResumeURL(c.Request.Context(), c.Param("id"), expires, c.Query("signature"))
+	if err != nil {
+		c.JSON(exportErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
+
+func exportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrJobNotFound), errors.Is(err, app.ErrApplicationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized), errors.Is(err, app.ErrInvalidLink):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
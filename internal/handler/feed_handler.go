@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/feed"
+)
+
+// MIMEJobPosting is the media type a client asks for in Accept to get a job
+// as schema.org JobPosting JSON-LD
+const MIMEJobPosting = "application/ld+json"
+
+// feedMaxAge is how long clients and proxies may cache a feed, in seconds
+const feedMaxAge = "300"
+
+type FeedHandler struct {
+	App app.FeedApp
+}
+
+func NewFeedHandler(app app.FeedApp) *FeedHandler {
+	return &FeedHandler{App: app}
+}
+
+// JobsRSS godoc
+// @Summary Job feed (RSS)
+// @Description The 50 newest published jobs as an RSS 2.0 feed (public)
+// @Tags Feeds
+// @Produce application/rss+xml
+// @Success 200 {string} string "RSS feed"
+// @Router /jobs/feed.rss [get]
+func (h *FeedHandler) JobsRSS(c *gin.Context) {
+	f, err := h.App.JobsFeed(c.Request.Context())
+	h.render(c, f, err, feed.RSS, "application/rss+xml; charset=utf-8")
+}
+
+// JobsAtom godoc
+// @Summary Job feed (Atom)
+// @Description The 50 newest published jobs as an Atom 1.0 feed (public)
+// @Tags Feeds
+// @Produce application/atom+xml
+// @Success 200 {string} string "Atom feed"
+// @Router /jobs/feed.atom [get]
+func (h *FeedHandler) JobsAtom(c *gin.Context) {
+	f, err := h.App.JobsFeed(c.Request.Context())
+	h.render(c, f, err, feed.Atom, "application/atom+xml; charset=utf-8")
+}
+
+// CompanyRSS godoc
+// @Summary Company job feed (RSS)
+// @Description A company's 50 newest published jobs as an RSS 2.0 feed (public)
+// @Tags Feeds
+// @Produce application/rss+xml
+// @Param id path string true "Company ID"
+// @Success 200 {string} string "RSS feed"
+// @Failure 404 {object} domain.BaseResponse
+// @Router /companies/{id}/feed.rss [get]
+func (h *FeedHandler) CompanyRSS(c *gin.Context) {
+	f, err := h.App.CompanyFeed(c.Request.Context(), c.Param("id"))
+	h.render(c, f, err, feed.RSS, "application/rss+xml; charset=utf-8")
+}
+
+// CompanyAtom godoc
+// @Summary Company job feed (Atom)
+// @Description A company's 50 newest published jobs as an Atom 1.0 feed (public)
+// @Tags Feeds
+// @Produce application/atom+xml
+// @Param id path string true "Company ID"
+// @Success 200 {string} string "Atom feed"
+// @Failure 404 {object} domain.BaseResponse
+// @Router /companies/{id}/feed.atom [get]
+func (h *FeedHandler) CompanyAtom(c *gin.Context) {
+	f, err := h.App.CompanyFeed(c.Request.Context(), c.Param("id"))
+	h.render(c, f, err, feed.Atom, "application/atom+xml; charset=utf-8")
+}
+
+func (h *FeedHandler) render(c *gin.Context, f *feed.Feed, err error, encode func(feed.Feed) ([]byte, error), contentType string) {
+	if err != nil {
+		c.JSON(feedErrorStatus(err), domain.BaseResponse{Success: false, Message: "Failed to build feed"})
+		return
+	}
+	body, err := encode(*f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to build feed"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age="+feedMaxAge)
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// jobPosting serves a job as JSON-LD; GetJob hands over to it when the
+// client asks for MIMEJobPosting
+func jobPosting(c *gin.Context, feeds app.FeedApp) {
+	posting, err := feeds.JobPosting(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if errors.Is(err, app.ErrNoJobPosting) {
+		c.JSON(http.StatusNotAcceptable, domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(feedErrorStatus(err), domain.BaseResponse{Success: false, Message: "Job not found"})
+		return
+	}
+	body, err := json.Marshal(posting)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to build job posting"})
+		return
+	}
+	c.Data(http.StatusOK, MIMEJobPosting+"; charset=utf-8", body)
+}
+
+func feedErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrCompanyNotFound), errors.Is(err, app.ErrJobNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
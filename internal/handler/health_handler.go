@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/health"
+)
+
+type HealthHandler struct {
+	Checker   *health.Checker
+	Bootstrap app.BootstrapApp
+}
+
+func NewHealthHandler(checker *health.Checker, bootstrap app.BootstrapApp) *HealthHandler {
+	return &HealthHandler{Checker: checker, Bootstrap: bootstrap}
+}
+
+// Livez godoc
+// @Summary Liveness probe
+// @Description Reports that the process is up and serving HTTP. It never checks dependencies, so a database outage does not get the process restarted.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Checks the database, schema migrations, seed roles and file storage and reports the status and latency of each. Returns 503 if any check fails or the server is shutting down.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.Checker.Check(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Health godoc
+// @Summary Health check
+// @Description Reports whether the seed roles are present
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /health [get]
+func (h *HealthHandler) Health(c *gin.Context) {
+	seed, err := h.Bootstrap.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(200, gin.H{"status": "degraded", "seed": gin.H{"error": err.Error()}})
+		return
+	}
+	status := "ok"
+	if !seed.Complete {
+		status = "degraded"
+	}
+	c.JSON(200, gin.H{"status": status, "seed": seed})
+}
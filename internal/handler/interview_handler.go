@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type InterviewHandler struct {
+	App app.InterviewApp
+}
+
+func NewInterviewHandler(app app.InterviewApp) *InterviewHandler {
+	return &InterviewHandler{App: app}
+}
+
+type interviewSlotRequest struct {
+	StartsAt time.Time `json:"starts_at" example:"2026-11-02T09:00:00Z"`
+	EndsAt   time.Time `json:"ends_at" example:"2026-11-02T10:00:00Z"`
+}
+
+type interviewRequest struct {
+	// Slots the applicant can choose from, 1 to 5
+	Slots []interviewSlotRequest `json:"slots"`
+	// Location, MeetingURL or both are required
+	Location     string   `json:"location" example:"Bole Road 12, Addis Ababa"`
+	MeetingURL   string   `json:"meeting_url" example:"https://meet.example.com/abc"`
+	Interviewers []string `json:"interviewers" example:"Abebe Kebede"`
+	Notes        string   `json:"notes"`
+}
+
+func (r interviewRequest) plan() app.InterviewPlan {
+	slots := make([]domain.InterviewSlot, 0, len(r.Slots))
+	for _, s := range r.Slots {
+		slots = append(slots, domain.InterviewSlot{StartsAt: s.StartsAt, EndsAt: s.EndsAt})
+	}
+	return app.InterviewPlan{
+		Slots:        slots,
+		Location:     r.Location,
+		MeetingURL:   r.MeetingURL,
+		Interviewers: r.Interviewers,
+		Notes:        r.Notes,
+	}
+}
+
+type chooseSlotRequest struct {
+	// Slot is the index of the chosen slot in the interview's slots
+	Slot int `json:"slot" example:"0"`
+}
+
+type cancelInterviewRequest struct {
+	Reason string `json:"reason" example:"Position filled"`
+}
+
+// ProposeInterview godoc
+// @Summary Propose interview
+// @Description Company proposes interview slots for an application; the applicant is notified and picks one (requires Bearer token). Withdrawn applications return 409 and removed jobs 404
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param interviewRequest body interviewRequest true "Slots and details"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/interviews [post]
+func (h *InterviewHandler) ProposeInterview(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req interviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	interview, err := h.App.ProposeInterview(c.Request.Context(), actorFrom(c), c.Param("id"), req.plan())
+	if err != nil {
+		h.fail(c, "Interview not proposed", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Interview proposed", Object: interview})
+}
+
+// ApplicationInterviews godoc
+// @Summary List interviews of an application
+// @Description Company lists the interviews of an application sent to one of its jobs, newest first (requires Bearer token)
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/interviews [get]
+func (h *InterviewHandler) ApplicationInterviews(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	interviews, err := h.App.ApplicationInterviews(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(interviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Interviews found", Object: interviews})
+}
+
+// RescheduleInterview godoc
+// @Summary Reschedule interview
+// @Description Company replaces the slots and details of an interview; the applicant has to choose a slot again (requires Bearer token)
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Interview ID"
+// @Param interviewRequest body interviewRequest true "Slots and details"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/interviews/{id}/reschedule [post]
+func (h *InterviewHandler) RescheduleInterview(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req interviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	interview, err := h.App.Reschedule(c.Request.Context(), actorFrom(c), c.Param("id"), req.plan())
+	if err != nil {
+		h.fail(c, "Interview not rescheduled", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Interview rescheduled", Object: interview})
+}
+
+// ChooseInterviewSlot godoc
+// @Summary Choose interview slot
+// @Description Applicant books one of the proposed slots; the company is notified (requires Bearer token)
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Interview ID"
+// @Param chooseSlotRequest body chooseSlotRequest true "Slot index"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/interviews/{id}/choose [post]
+func (h *InterviewHandler) ChooseInterviewSlot(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req chooseSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	interview, err := h.App.ChooseSlot(c.Request.Context(), actorFrom(c), c.Param("id"), req.Slot)
+	if err != nil {
+		h.fail(c, "Slot not booked", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Interview scheduled", Object: interview})
+}
+
+// CancelInterview godoc
+// @Summary Cancel interview
+// @Description Company or applicant cancels an interview with an optional reason; the other side is notified (requires Bearer token)
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Interview ID"
+// @Param cancelInterviewRequest body cancelInterviewRequest false "Reason"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/interviews/{id}/cancel [post]
+// @Router /applicant/interviews/{id}/cancel [post]
+func (h *InterviewHandler) CancelInterview(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req cancelInterviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+			return
+		}
+	}
+	interview, err := h.App.Cancel(c.Request.Context(), actorFrom(c), c.Param("id"), req.Reason)
+	if err != nil {
+		h.fail(c, "Interview not cancelled", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Interview cancelled", Object: interview})
+}
+
+// ApplicantInterviews godoc
+// @Summary List my interviews
+// @Description Applicant lists their interviews across all applications, newest first (requires Bearer token)
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /applicant/interviews [get]
+func (h *InterviewHandler) ApplicantInterviews(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	interviews, info, err := h.App.ApplicantInterviews(c.Request.Context(), actorFrom(c), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(interviewErrorStatus(err), domain.PaginatedResponse{Success: false, Message: "Failed to fetch interviews"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Interviews found", interviews, q, info))
+}
+
+// GetInterview godoc
+// @Summary Get interview
+// @Description The company, the applicant or an admin views an interview (requires Bearer token)
+// @Tags Interviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Interview ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /interviews/{id} [get]
+func (h *InterviewHandler) GetInterview(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	interview, err := h.App.GetInterview(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(interviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Interview found", Object: interview})
+}
+
+// InterviewCalendar godoc
+// @Summary Download interview calendar file
+// @Description Returns a scheduled or cancelled interview as an iCalendar (.ics) file; importing the file of a cancelled interview removes it from the calendar (requires Bearer token)
+// @Tags Interviews
+// @Produce text/calendar
+// @Param id path string true "Interview ID"
+// @Success 200 {string} string "iCalendar file"
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /interviews/{id}/ics [get]
+func (h *InterviewHandler) InterviewCalendar(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	ics, err := h.App.Calendar(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(interviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="interview.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", ics)
+}
+
+func (h *InterviewHandler) fail(c *gin.Context, message string, err error) {
+	var verr app.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: message, Errors: verr})
+		return
+	}
+	c.JSON(interviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+}
+
+func interviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrInterviewNotFound), errors.Is(err, app.ErrApplicationNotFound), errors.Is(err, app.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrInterviewCancelled), errors.Is(err, app.ErrSlotAlreadyChosen), errors.Is(err, app.ErrInterviewNotScheduled), errors.Is(err, app.ErrAlreadyWithdrawn):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"strconv"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,11 +16,12 @@ import (
 )
 
 type JobHandler struct {
-	App app.JobApp
+	App   app.JobApp
+	Feeds app.FeedApp
 }
 
-func NewJobHandler(app app.JobApp) *JobHandler {
-	return &JobHandler{App: app}
+func NewJobHandler(app app.JobApp, feeds app.FeedApp) *JobHandler {
+	return &JobHandler{App: app, Feeds: feeds}
 }
 
 type jobRequest struct {
@@ -27,7 +32,7 @@ type jobRequest struct {
 
 // CreateJob godoc
 // @Summary Create job
-// @Description Company posts a new job (requires Bearer token)
+// @Description Company posts a new job; jobs tripping the auto-hold rules are held for review (requires Bearer token)
 // @Tags Jobs
 // @Accept json
 // @Produce json
@@ -47,12 +52,8 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
 		return
 	}
-	if len(req.Title) < 1 || len(req.Title) > 100 {
-		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Title must be 1-100 characters"})
-		return
-	}
-	if len(req.Description) < 20 || len(req.Description) > 2000 {
-		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Description must be 20-2000 characters"})
+	if problems := app.ValidateJob(req.Title, req.Description); len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: problems[0]})
 		return
 	}
 	userID := c.GetString("user_id")
@@ -63,13 +64,106 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 		Location:    req.Location,
 		CreatedBy:   uuid.MustParse(userID),
 	}
-	if err := h.App.CreateJob(c.Request.Context(), job); err != nil {
+	err := h.App.CreateJob(c.Request.Context(), actorFrom(c), job)
+	if errors.Is(err, app.ErrUnauthorized) {
+		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to create job"})
 		return
 	}
+	if job.Status == domain.JobStatusHeld {
+		c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job submitted for review", Object: job})
+		return
+	}
 	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job created", Object: job})
 }
 
+// maxImportBytes caps the size of a bulk import upload
+const maxImportBytes = 5 << 20
+
+// ImportJobs godoc
+// @Summary Bulk import jobs
+// @Description Company posts many jobs at once from a JSON array or a CSV file with a header row (title, description, location). Send the body as application/json or text/csv, or upload it as the "file" field of a multipart form. Every row is checked with the same rules as creating a single job and errors are reported per row; the valid rows are stored together in one transaction. With dry_run=true nothing is stored (requires Bearer token)
+// @Tags Jobs
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param dry_run query bool false "Validate without storing"
+// @Param file formData file false "CSV or JSON file"
+// @Success 200 {object} domain.BaseResponse{object=domain.JobImportResult}
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 413 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/jobs/import [post]
+func (h *JobHandler) ImportJobs(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	body, contentType := io.Reader(c.Request.Body), c.ContentType()
+	if strings.HasPrefix(contentType, "multipart/") {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			importFail(c, err)
+			return
+		}
+		defer file.Close()
+		body, contentType = file, "application/json"
+		if strings.EqualFold(filepath.Ext(header.Filename), ".csv") {
+			contentType = "text/csv"
+		}
+	}
+	var inputs []app.JobInput
+	var err error
+	switch contentType {
+	case "application/json":
+		inputs, err = app.ParseJobsJSON(body)
+	case "text/csv":
+		inputs, err = app.ParseJobsCSV(body)
+	default:
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Content-Type must be application/json, text/csv or multipart/form-data"})
+		return
+	}
+	if err != nil {
+		importFail(c, err)
+		return
+	}
+	result, err := h.App.ImportJobs(c.Request.Context(), actorFrom(c), inputs, dryRun)
+	if err != nil {
+		importFail(c, err)
+		return
+	}
+	message := fmt.Sprintf("Imported %d of %d jobs", result.Imported, result.Total)
+	if dryRun {
+		message = fmt.Sprintf("%d of %d jobs are valid", result.Total-result.Invalid, result.Total)
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: message, Object: result})
+}
+
+func importFail(c *gin.Context, err error) {
+	var verr app.ValidationError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &verr):
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: verr})
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, domain.BaseResponse{Success: false, Message: fmt.Sprintf("Upload must be at most %d MB", maxImportBytes>>20)})
+	case errors.Is(err, http.ErrMissingFile):
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Missing file"}})
+	case errors.Is(err, app.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to import jobs"})
+	}
+}
+
 // UpdateJob godoc
 // @Summary Update job
 // @Description Company updates their job (requires Bearer token)
@@ -80,6 +174,8 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 // @Param jobRequest body jobRequest true "Job request"
 // @Success 200 {object} domain.BaseResponse
 // @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
 // @Security BearerAuth
 // @Router /company/jobs/{id} [put]
 func (h *JobHandler) UpdateJob(c *gin.Context) {
@@ -94,20 +190,21 @@ func (h *JobHandler) UpdateJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input"})
 		return
 	}
-	userID := c.GetString("user_id")
-	job, err := h.App.GetJobByID(c.Request.Context(), id)
+	jobID, err := uuid.Parse(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, domain.BaseResponse{Success: false, Message: "Job not found"})
 		return
 	}
-	if job.CreatedBy.String() != userID {
+	changes := &domain.Job{ID: jobID, Title: req.Title, Description: req.Description, Location: req.Location}
+	job, err := h.App.UpdateJob(c.Request.Context(), actorFrom(c), changes)
+	switch {
+	case errors.Is(err, app.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, domain.BaseResponse{Success: false, Message: "Job not found"})
+		return
+	case errors.Is(err, app.ErrUnauthorized):
 		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: "Unauthorized access"})
 		return
-	}
-	job.Title = req.Title
-	job.Description = req.Description
-	job.Location = req.Location
-	if err := h.App.UpdateJob(c.Request.Context(), job); err != nil {
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to update job"})
 		return
 	}
@@ -116,13 +213,14 @@ func (h *JobHandler) UpdateJob(c *gin.Context) {
 
 // DeleteJob godoc
 // @Summary Delete job
-// @Description Company deletes their job (requires Bearer token)
+// @Description Company deletes their job (requires Bearer token). The job is taken off the site like an admin takedown; its applications are kept
 // @Tags Jobs
 // @Accept json
 // @Produce json
 // @Param id path string true "Job ID"
 // @Success 200 {object} domain.BaseResponse
-// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
 // @Security BearerAuth
 // @Router /company/jobs/{id} [delete]
 func (h *JobHandler) DeleteJob(c *gin.Context) {
@@ -132,27 +230,41 @@ func (h *JobHandler) DeleteJob(c *gin.Context) {
 		return
 	}
 	id := c.Param("id")
-	userID := c.GetString("user_id")
-	if err := h.App.DeleteJob(c.Request.Context(), id, userID); err != nil {
+	err := h.App.DeleteJob(c.Request.Context(), actorFrom(c), id)
+	switch {
+	case errors.Is(err, app.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, domain.BaseResponse{Success: false, Message: "Job not found"})
+		return
+	case errors.Is(err, app.ErrUnauthorized):
 		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: "Unauthorized access"})
 		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to delete job"})
+		return
 	}
 	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job deleted"})
 }
 
 // GetJob godoc
 // @Summary Get job details
-// @Description Get job details by ID (public)
+// @Description Get job details by ID (public). Send Accept: application/ld+json to get the job as schema.org JobPosting JSON-LD instead; jobs without a location, and remote jobs when FEED_REMOTE_COUNTRY is unset, return 406 for it
 // @Tags Jobs
 // @Accept json
 // @Produce json
+// @Produce application/ld+json
 // @Param id path string true "Job ID"
 // @Success 200 {object} domain.BaseResponse
 // @Failure 404 {object} domain.BaseResponse
+// @Failure 406 {object} domain.BaseResponse
 // @Router /jobs/{id} [get]
 func (h *JobHandler) GetJob(c *gin.Context) {
+	c.Header("Vary", "Accept")
+	if c.NegotiateFormat(gin.MIMEJSON, MIMEJobPosting) == MIMEJobPosting {
+		jobPosting(c, h.Feeds)
+		return
+	}
 	id := c.Param("id")
-	job, err := h.App.GetJobByID(c.Request.Context(), id)
+	job, err := h.App.GetJobByID(c.Request.Context(), actorFrom(c), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, domain.BaseResponse{Success: false, Message: "Job not found"})
 		return
@@ -169,9 +281,13 @@ func (h *JobHandler) GetJob(c *gin.Context) {
 // @Param title query string false "Job title"
 // @Param location query string false "Location"
 // @Param company_name query string false "Company name"
+// @Param company_id query string false "Company ID"
+// @Param posted_since query string false "Only jobs posted at or after this time (RFC 3339 or YYYY-MM-DD)"
 // @Param page query int false "Page number"
-// @Param size query int false "Page size"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
 // @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
 // @Security BearerAuth
 // @Router /applicant/jobs [get]
 func (h *JobHandler) SearchJobs(c *gin.Context) {
@@ -180,6 +296,33 @@ func (h *JobHandler) SearchJobs(c *gin.Context) {
 		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
 		return
 	}
+	h.search(c, "Failed to search jobs")
+}
+
+// ListJobs godoc
+// @Summary List jobs
+// @Description Lists published jobs, newest first, with the same filters as the applicant search (public)
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param title query string false "Job title"
+// @Param location query string false "Location"
+// @Param company_name query string false "Company name"
+// @Param company_id query string false "Company ID"
+// @Param posted_since query string false "Only jobs posted at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Router /jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	// Public endpoint: do not check for Authorization header
+	h.search(c, "Failed to fetch jobs")
+}
+
+// search runs a job search with the filters in the query string
+func (h *JobHandler) search(c *gin.Context, failure string) {
 	filters := map[string]interface{}{}
 	if title := c.Query("title"); title != "" {
 		filters["title"] = title
@@ -190,48 +333,34 @@ func (h *JobHandler) SearchJobs(c *gin.Context) {
 	if company := c.Query("company_name"); company != "" {
 		filters["company_name"] = company
 	}
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
-	jobs, total, err := h.App.SearchJobs(c.Request.Context(), filters, page, size)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.PaginatedResponse{Success: false, Message: "Failed to search jobs"})
+	if companyID := c.Query("company_id"); companyID != "" {
+		id, err := uuid.Parse(companyID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: "company_id must be a UUID"})
+			return
+		}
+		filters["company_id"] = id
+	}
+	if since := c.Query("posted_since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, since)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: "posted_since must be an RFC 3339 time or a YYYY-MM-DD date"})
+			return
+		}
+		filters["posted_since"] = t
+	}
+	q := pageQuery(c)
+	jobs, info, err := h.App.SearchJobs(c.Request.Context(), filters, q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, domain.PaginatedResponse{
-		Success:    true,
-		Message:    "Jobs found",
-		Object:     jobs,
-		PageNumber: page,
-		PageSize:   size,
-		TotalSize:  int(total),
-	})
-}
-
-// GetJobsByCompany godoc
-// @Summary List all company jobs
-// @Description List all company jobs (public)
-// @Tags Jobs
-// @Accept json
-// @Produce json
-// @Param page query int false "Page number"
-// @Param size query int false "Page size"
-// @Success 200 {object} domain.PaginatedResponse
-// @Router /jobs [get]
-func (h *JobHandler) GetJobsByCompany(c *gin.Context) {
-	// Public endpoint: do not check for Authorization header
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
-	jobs, total, err := h.App.GetJobsByCompany(c.Request.Context(), "", page, size)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.PaginatedResponse{Success: false, Message: "Failed to fetch jobs"})
+		c.JSON(http.StatusInternalServerError, domain.PaginatedResponse{Success: false, Message: failure})
 		return
 	}
-	c.JSON(http.StatusOK, domain.PaginatedResponse{
-		Success:    true,
-		Message:    "Jobs found",
-		Object:     jobs,
-		PageNumber: page,
-		PageSize:   size,
-		TotalSize:  int(total),
-	})
+	c.JSON(http.StatusOK, paginatedResponse("Jobs found", jobs, q, info))
 }
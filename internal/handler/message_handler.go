@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type MessageHandler struct {
+	App app.MessageApp
+}
+
+func NewMessageHandler(app app.MessageApp) *MessageHandler {
+	return &MessageHandler{App: app}
+}
+
+type messageRequest struct {
+	Body string `form:"body" json:"body" example:"Could you share your availability next week?"`
+}
+
+// SendMessage godoc
+// @Summary Send message
+// @Description The applicant or the company that owns the job posts to the application's thread. Send JSON, or multipart/form-data with an optional attachment file (pdf, doc, docx, txt, png, jpg; max 10 MB). The other side is notified (requires Bearer token)
+// @Tags Messages
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param body formData string false "Message text"
+// @Param attachment formData file false "Attachment"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 410 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applications/{id}/messages [post]
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req messageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid message"}})
+		return
+	}
+	var attachment *app.Attachment
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, header, err := c.Request.FormFile("attachment")
+		switch {
+		case err == nil:
+			defer file.Close()
+			attachment = &app.Attachment{File: file, Name: header.Filename, Size: header.Size}
+		case !errors.Is(err, http.ErrMissingFile):
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid attachment"}})
+			return
+		}
+	}
+	message, err := h.App.SendMessage(c.Request.Context(), actorFrom(c), c.Param("id"), req.Body, attachment)
+	if err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Message not sent", Errors: verr})
+			return
+		}
+		c.JSON(messageErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Message sent", Object: message})
+}
+
+// Thread godoc
+// @Summary List messages
+// @Description The applicant or the company that owns the job reads the application's thread, newest first (requires Bearer token)
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Failure 403 {object} domain.PaginatedResponse
+// @Failure 404 {object} domain.PaginatedResponse
+// @Failure 410 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /applications/{id}/messages [get]
+func (h *MessageHandler) Thread(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	messages, info, err := h.App.Thread(c.Request.Context(), actorFrom(c), c.Param("id"), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(messageErrorStatus(err), domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Messages found", messages, q, info))
+}
+
+// MarkThreadRead godoc
+// @Summary Mark messages read
+// @Description Marks every message the caller received in the application's thread as read (requires Bearer token)
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 410 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applications/{id}/messages/read [post]
+func (h *MessageHandler) MarkThreadRead(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	if err := h.App.MarkRead(c.Request.Context(), actorFrom(c), c.Param("id")); err != nil {
+		c.JSON(messageErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Messages marked as read"})
+}
+
+// UnreadMessages godoc
+// @Summary Unread message counts
+// @Description Unread messages per application thread and in total; hidden threads are not counted (requires Bearer token)
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /messages/unread_count [get]
+func (h *MessageHandler) UnreadMessages(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	threads, total, err := h.App.UnreadCounts(c.Request.Context(), actorFrom(c))
+	if err != nil {
+		c.JSON(messageErrorStatus(err), domain.BaseResponse{Success: false, Message: "Failed to count messages"})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Unread messages", Object: gin.H{"unread": total, "threads": threads}})
+}
+
+func messageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrApplicationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrMessagesHidden):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type ModerationHandler struct {
+	App app.ModerationApp
+}
+
+func NewModerationHandler(app app.ModerationApp) *ModerationHandler {
+	return &ModerationHandler{App: app}
+}
+
+type reportRequest struct {
+	Reason  string `json:"reason" example:"scam"`
+	Details string `json:"details"`
+}
+
+// ReportJob godoc
+// @Summary Report job
+// @Description Applicant flags a job as scam, discriminatory, spam or other (requires Bearer token)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param reportRequest body reportRequest true "Report"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/jobs/{id}/report [post]
+func (h *ModerationHandler) ReportJob(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req reportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	report, err := h.App.ReportJob(c.Request.Context(), actorFrom(c), c.Param("id"), req.Reason, req.Details)
+	if err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Report failed", Errors: verr})
+			return
+		}
+		c.JSON(moderationErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job reported", Object: report})
+}
+
+// Queue godoc
+// @Summary Moderation queue
+// @Description Admin lists held jobs and jobs with unresolved reports (requires Bearer token)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /admin/moderation/jobs [get]
+func (h *ModerationHandler) Queue(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	items, info, err := h.App.Queue(c.Request.Context(), actorFrom(c), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(moderationErrorStatus(err), domain.PaginatedResponse{Success: false, Message: "Failed to fetch moderation queue"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Moderation queue", items, q, info))
+}
+
+// Approve godoc
+// @Summary Approve job
+// @Description Admin publishes a held job and dismisses its reports (requires Bearer token)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /admin/moderation/jobs/{id}/approve [post]
+func (h *ModerationHandler) Approve(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	job, err := h.App.Approve(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(moderationErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job approved", Object: job})
+}
+
+// Reject godoc
+// @Summary Reject job
+// @Description Admin removes a held or reported job and closes its reports (requires Bearer token)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /admin/moderation/jobs/{id}/reject [post]
+func (h *ModerationHandler) Reject(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	job, err := h.App.Reject(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(moderationErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Job rejected", Object: job})
+}
+
+func moderationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrAlreadyReported), errors.Is(err, app.ErrNotInQueue):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type NotificationHandler struct {
+	App app.NotificationApp
+}
+
+func NewNotificationHandler(app app.NotificationApp) *NotificationHandler {
+	return &NotificationHandler{App: app}
+}
+
+type preferencesRequest struct {
+	Preferences []domain.NotificationPreference `json:"preferences"`
+}
+
+// Inbox godoc
+// @Summary Notification inbox
+// @Description Lists the caller's in-app notifications, newest first (requires Bearer token)
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /notifications [get]
+func (h *NotificationHandler) Inbox(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var unreadOnly bool
+	if unread := c.Query("unread"); unread != "" {
+		value, err := strconv.ParseBool(unread)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: "unread must be true or false"})
+			return
+		}
+		unreadOnly = value
+	}
+	q := pageQuery(c)
+	notifications, info, err := h.App.Inbox(c.Request.Context(), actorFrom(c), unreadOnly, q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), domain.PaginatedResponse{Success: false, Message: "Failed to fetch notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Notifications found", notifications, q, info))
+}
+
+// UnreadCount godoc
+// @Summary Unread notification count
+// @Description Number of unread notifications in the caller's inbox (requires Bearer token)
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /notifications/unread_count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	count, err := h.App.UnreadCount(c.Request.Context(), actorFrom(c))
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), domain.BaseResponse{Success: false, Message: "Failed to count notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Unread notifications", Object: gin.H{"unread": count}})
+}
+
+// MarkRead godoc
+// @Summary Mark notification read
+// @Description Marks one of the caller's notifications as read (requires Bearer token)
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	notification, err := h.App.MarkRead(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Notification marked as read", Object: notification})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications read
+// @Description Marks every notification in the caller's inbox as read (requires Bearer token)
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /notifications/read_all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	if err := h.App.MarkAllRead(c.Request.Context(), actorFrom(c)); err != nil {
+		c.JSON(notificationErrorStatus(err), domain.BaseResponse{Success: false, Message: "Failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "All notifications marked as read"})
+}
+
+// Preferences godoc
+// @Summary Notification preferences
+// @Description Lists, for every notification type, whether the caller receives it in the inbox and by email (requires Bearer token)
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) Preferences(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	prefs, err := h.App.Preferences(c.Request.Context(), actorFrom(c))
+	if err != nil {
+		c.JSON(notificationErrorStatus(err), domain.BaseResponse{Success: false, Message: "Failed to fetch preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Notification preferences", Object: prefs})
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Sets inbox and email delivery per notification type; types left out keep their setting (requires Bearer token)
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param preferencesRequest body preferencesRequest true "Preferences"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req preferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	prefs, err := h.App.UpdatePreferences(c.Request.Context(), actorFrom(c), req.Preferences)
+	if err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Preferences not saved", Errors: verr})
+			return
+		}
+		c.JSON(notificationErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Notification preferences updated", Object: prefs})
+}
+
+func notificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrNotificationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+// pageQuery reads the page, size and cursor query parameters. The page size
+// is capped at domain.MaxPageSize.
+func pageQuery(c *gin.Context) domain.PageQuery {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(domain.DefaultPageSize)))
+	return domain.PageQuery{Page: page, Size: size, Cursor: c.Query("cursor")}.Normalize()
+}
+
+// paginatedResponse reports the page number only for offset paging; a page
+// reached through a cursor has none.
+func paginatedResponse(message string, object interface{}, q domain.PageQuery, info domain.PageInfo) domain.PaginatedResponse {
+	resp := domain.PaginatedResponse{
+		Success:    true,
+		Message:    message,
+		Object:     object,
+		PageSize:   q.Size,
+		TotalSize:  int(info.Total),
+		NextCursor: info.NextCursor,
+		PrevCursor: info.PrevCursor,
+	}
+	if q.Cursor == "" {
+		resp.PageNumber = q.Page
+	}
+	return resp
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type RecommendationHandler struct {
+	App app.RecommendationApp
+}
+
+func NewRecommendationHandler(app app.RecommendationApp) *RecommendationHandler {
+	return &RecommendationHandler{App: app}
+}
+
+// Recommend godoc
+// @Summary Recommended jobs
+// @Description Ranks open jobs the applicant has not applied to by their profile skills and location, saved searches and past applications. Each job comes with its score and a short reason (requires Bearer token)
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of jobs (default 20, max 50)"
+// @Success 200 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/recommendations [get]
+func (h *RecommendationHandler) Recommend(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(app.DefaultRecommendations)))
+	recs, err := h.App.Recommend(c.Request.Context(), actorFrom(c), limit)
+	if errors.Is(err, app.ErrUnauthorized) {
+		c.JSON(http.StatusForbidden, domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: "Failed to recommend jobs"})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Recommended jobs", Object: recs})
+}
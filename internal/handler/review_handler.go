@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type ReviewHandler struct {
+	App app.ReviewApp
+}
+
+func NewReviewHandler(app app.ReviewApp) *ReviewHandler {
+	return &ReviewHandler{App: app}
+}
+
+type noteRequest struct {
+	Body string `json:"body" example:"Strong Go background, weak on SQL"`
+}
+
+type ratingRequest struct {
+	// Rating from 1 to 5
+	Rating int `json:"rating" example:"4"`
+}
+
+type tagsRequest struct {
+	Tags []string `json:"tags" example:"shortlist"`
+}
+
+// GetReview godoc
+// @Summary Get application review
+// @Description Company sees its private notes, ratings, average rating and tags for an application (requires Bearer token)
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/review [get]
+func (h *ReviewHandler) GetReview(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	review, err := h.App.GetReview(c.Request.Context(), actorFrom(c), c.Param("id"))
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Review found", Object: review})
+}
+
+// AddNote godoc
+// @Summary Add note
+// @Description Company adds a private note to an application; applicants never see notes (requires Bearer token)
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param noteRequest body noteRequest true "Note"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/notes [post]
+func (h *ReviewHandler) AddNote(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req noteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	note, err := h.App.AddNote(c.Request.Context(), actorFrom(c), c.Param("id"), req.Body)
+	if err != nil {
+		reviewFail(c, "Note not added", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Note added", Object: note})
+}
+
+// DeleteNote godoc
+// @Summary Delete note
+// @Description Company deletes a note it wrote on an application (requires Bearer token)
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param note_id path string true "Note ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/notes/{note_id} [delete]
+func (h *ReviewHandler) DeleteNote(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	if err := h.App.DeleteNote(c.Request.Context(), actorFrom(c), c.Param("id"), c.Param("note_id")); err != nil {
+		c.JSON(reviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Note deleted"})
+}
+
+// RateApplication godoc
+// @Summary Rate application
+// @Description Company rates an application from 1 to 5; rating again replaces the caller's earlier rating. Returns the updated review with the average rating (requires Bearer token)
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param ratingRequest body ratingRequest true "Rating"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/rating [put]
+func (h *ReviewHandler) RateApplication(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req ratingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	review, err := h.App.Rate(c.Request.Context(), actorFrom(c), c.Param("id"), req.Rating)
+	if err != nil {
+		reviewFail(c, "Rating not saved", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Rating saved", Object: review})
+}
+
+// SetTags godoc
+// @Summary Set application tags
+// @Description Company replaces the tags of an application. Tags are lowercased; filter a job's applications by them with ?tag= (requires Bearer token)
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param tagsRequest body tagsRequest true "Tags"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/applications/{id}/tags [put]
+func (h *ReviewHandler) SetTags(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req tagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	review, err := h.App.SetTags(c.Request.Context(), actorFrom(c), c.Param("id"), req.Tags)
+	if err != nil {
+		reviewFail(c, "Tags not saved", err)
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Tags saved", Object: review})
+}
+
+func reviewFail(c *gin.Context, message string, err error) {
+	var verr app.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: message, Errors: verr})
+		return
+	}
+	c.JSON(reviewErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrApplicationNotFound), errors.Is(err, app.ErrJobNotFound), errors.Is(err, app.ErrNoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
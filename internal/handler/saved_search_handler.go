@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type SavedSearchHandler struct {
+	App app.SavedSearchApp
+}
+
+func NewSavedSearchHandler(app app.SavedSearchApp) *SavedSearchHandler {
+	return &SavedSearchHandler{App: app}
+}
+
+type savedSearchRequest struct {
+	Name        string `json:"name" example:"Remote Go jobs"`
+	Title       string `json:"title" example:"golang"`
+	Location    string `json:"location" example:"Remote"`
+	CompanyName string `json:"company_name"`
+	// Frequency is daily (the default), weekly or off
+	Frequency string `json:"frequency" example:"daily"`
+}
+
+func (r savedSearchRequest) savedSearch() *domain.SavedSearch {
+	return &domain.SavedSearch{
+		Name:        r.Name,
+		Title:       r.Title,
+		Location:    r.Location,
+		CompanyName: r.CompanyName,
+		Frequency:   domain.AlertFrequency(r.Frequency),
+	}
+}
+
+// CreateSavedSearch godoc
+// @Summary Save search
+// @Description Applicant saves a set of /applicant/jobs filters under a name. New matching jobs are sent as a daily or weekly digest unless frequency is off (requires Bearer token)
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Param savedSearchRequest body savedSearchRequest true "Saved search"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/saved_searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	search := req.savedSearch()
+	if err := h.App.CreateSavedSearch(c.Request.Context(), actorFrom(c), search); err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Search not saved", Errors: verr})
+			return
+		}
+		c.JSON(savedSearchErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Search saved", Object: search})
+}
+
+// ListSavedSearches godoc
+// @Summary List saved searches
+// @Description Applicant lists their saved searches (requires Bearer token)
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /applicant/saved_searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	searches, info, err := h.App.ListSavedSearches(c.Request.Context(), actorFrom(c), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(savedSearchErrorStatus(err), domain.PaginatedResponse{Success: false, Message: "Failed to fetch saved searches"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Saved searches found", searches, q, info))
+}
+
+// UpdateSavedSearch godoc
+// @Summary Update saved search
+// @Description Applicant changes the name, filters or alert frequency of a saved search; an empty frequency keeps the current one (requires Bearer token)
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Param savedSearchRequest body savedSearchRequest true "Saved search"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/saved_searches/{id} [put]
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	searchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.BaseResponse{Success: false, Message: app.ErrSavedSearchNotFound.Error()})
+		return
+	}
+	changes := req.savedSearch()
+	changes.ID = searchID
+	search, err := h.App.UpdateSavedSearch(c.Request.Context(), actorFrom(c), changes)
+	if err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Saved search not updated", Errors: verr})
+			return
+		}
+		c.JSON(savedSearchErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Saved search updated", Object: search})
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete saved search
+// @Description Applicant deletes a saved search and its alerts (requires Bearer token)
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /applicant/saved_searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	if err := h.App.DeleteSavedSearch(c.Request.Context(), actorFrom(c), c.Param("id")); err != nil {
+		c.JSON(savedSearchErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Saved search deleted"})
+}
+
+// RunSavedSearch godoc
+// @Summary Run saved search
+// @Description Applicant runs a saved search; same results as /applicant/jobs with the saved filters (requires Bearer token)
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Param id path string true "Saved search ID"
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Failure 404 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /applicant/saved_searches/{id}/jobs [get]
+func (h *SavedSearchHandler) RunSavedSearch(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	jobs, info, err := h.App.RunSavedSearch(c.Request.Context(), actorFrom(c), c.Param("id"), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(savedSearchErrorStatus(err), domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Jobs found", jobs, q, info))
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe from job alerts
+// @Description Turns off the alerts of a saved search; linked from every digest, so it needs no login (public)
+// @Tags Saved Searches
+// @Accept json
+// @Produce json
+// @Param token query string true "Unsubscribe token from the digest"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Router /alerts/unsubscribe [get]
+func (h *SavedSearchHandler) Unsubscribe(c *gin.Context) {
+	search, err := h.App.Unsubscribe(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		c.JSON(savedSearchErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "You will no longer receive alerts for " + search.Name})
+}
+
+func savedSearchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrSavedSearchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrTooManySavedSearches):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
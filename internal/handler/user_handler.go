@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -19,6 +20,9 @@ type UserSwagger struct {
 	Name  string `json:"name" example:"John Doe"`
 	Email string `json:"email" example:"john@example.com"`
 	Role  string `json:"role" example:"applicant"`
+	// Skills and Location are the applicant profile used for recommendations
+	Skills   []string `json:"skills" example:"go,postgresql"`
+	Location string   `json:"location" example:"Addis Ababa"`
 }
 
 type UserHandler struct {
@@ -49,6 +53,11 @@ type loginRequest struct {
 	Password string `json:"password"`
 }
 
+type profileRequest struct {
+	Skills   []string `json:"skills" example:"go,postgresql,docker"`
+	Location string   `json:"location" example:"Addis Ababa"`
+}
+
 // Signup godoc
 // @Summary Register as a new user (company or applicant)
 // @Description Register as a new user (company or applicant)
@@ -127,3 +136,38 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, user)
 }
+
+// UpdateProfile godoc
+// @Summary Update profile
+// @Description Replaces the caller's skills and location, which are used to recommend jobs (requires Bearer token)
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param profileRequest body profileRequest true "Profile"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /user/me/profile [put]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req profileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	user, err := h.App.UpdateProfile(c.Request.Context(), c.GetString("user_id"), req.Skills, req.Location)
+	if err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Profile not updated", Errors: verr})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Profile updated", Object: user})
+}
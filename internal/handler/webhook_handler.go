@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type WebhookHandler struct {
+	App app.WebhookApp
+}
+
+func NewWebhookHandler(app app.WebhookApp) *WebhookHandler {
+	return &WebhookHandler{App: app}
+}
+
+type webhookRequest struct {
+	URL    string   `json:"url" example:"https://ats.example.com/hooks/sera-ale"`
+	Events []string `json:"events" example:"application.created,application.status_changed,job.closed"`
+}
+
+// CreateWebhook godoc
+// @Summary Register webhook
+// @Description Company registers an endpoint for application.created, application.status_changed and job.closed events. The signing secret is only returned here (requires Bearer token)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param webhookRequest body webhookRequest true "Webhook"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 400 {object} domain.BaseResponse
+// @Failure 409 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Invalid input", Errors: []string{"Invalid JSON"}})
+		return
+	}
+	hook, err := h.App.CreateWebhook(c.Request.Context(), actorFrom(c), req.URL, req.Events)
+	if err != nil {
+		var verr app.ValidationError
+		if errors.As(err, &verr) {
+			c.JSON(http.StatusBadRequest, domain.BaseResponse{Success: false, Message: "Webhook not created", Errors: verr})
+			return
+		}
+		c.JSON(webhookErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Webhook created", Object: hook})
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description Company lists its registered webhooks (requires Bearer token)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /company/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	hooks, info, err := h.App.ListWebhooks(c.Request.Context(), actorFrom(c), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), domain.PaginatedResponse{Success: false, Message: "Failed to fetch webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Webhooks found", hooks, q, info))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook
+// @Description Company removes a webhook together with its delivery log (requires Bearer token)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	if err := h.App.DeleteWebhook(c.Request.Context(), actorFrom(c), c.Param("id")); err != nil {
+		c.JSON(webhookErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Webhook deleted"})
+}
+
+// Deliveries godoc
+// @Summary Webhook delivery log
+// @Description Company lists the deliveries of a webhook with their status, attempts and last error (requires Bearer token)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param page query int false "Page number"
+// @Param size query int false "Page size (max 100)"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor; overrides page"
+// @Success 200 {object} domain.PaginatedResponse
+// @Failure 400 {object} domain.PaginatedResponse
+// @Failure 404 {object} domain.PaginatedResponse
+// @Security BearerAuth
+// @Router /company/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	q := pageQuery(c)
+	deliveries, info, err := h.App.Deliveries(c.Request.Context(), actorFrom(c), c.Param("id"), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), domain.PaginatedResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, paginatedResponse("Deliveries found", deliveries, q, info))
+}
+
+// Replay godoc
+// @Summary Replay delivery
+// @Description Company queues an earlier delivery to be sent again with the same payload (requires Bearer token)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} domain.BaseResponse
+// @Failure 403 {object} domain.BaseResponse
+// @Failure 404 {object} domain.BaseResponse
+// @Security BearerAuth
+// @Router /company/webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) Replay(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" || !strings.HasPrefix(token, "Bearer ") {
+		c.JSON(401, gin.H{"success": false, "message": "Missing or invalid Bearer token in Authorization header. Please provide: Authorization: Bearer <token>"})
+		return
+	}
+	delivery, err := h.App.Replay(c.Request.Context(), actorFrom(c), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(webhookErrorStatus(err), domain.BaseResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, domain.BaseResponse{Success: true, Message: "Delivery queued", Object: delivery})
+}
+
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, app.ErrWebhookNotFound), errors.Is(err, app.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrTooManyWebhooks):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,95 @@
+// Package ical writes single-event iCalendar (RFC 5545) files that calendar
+// clients can import or open from an email.
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const timeFormat = "20060102T150405Z"
+
+// Event is one VEVENT. Sending an event again with the same UID and a higher
+// Sequence updates it in the calendar; Cancelled removes it.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Cancelled   bool
+}
+
+// Encode renders e as a VCALENDAR with CRLF line endings and long lines
+// folded at 75 octets
+func Encode(e Event) []byte {
+	var b strings.Builder
+	method, status := "PUBLISH", "CONFIRMED"
+	if e.Cancelled {
+		method, status = "CANCEL", "CANCELLED"
+	}
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Sera Ale//Job Board//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:" + method,
+		"BEGIN:VEVENT",
+		"UID:" + e.UID,
+		fmt.Sprintf("SEQUENCE:%d", e.Sequence),
+		"DTSTAMP:" + e.Stamp.UTC().Format(timeFormat),
+		"DTSTART:" + e.Start.UTC().Format(timeFormat),
+		"DTEND:" + e.End.UTC().Format(timeFormat),
+		"SUMMARY:" + escape(e.Summary),
+		"STATUS:" + status,
+	}
+	if e.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escape(e.Description))
+	}
+	if e.Location != "" {
+		lines = append(lines, "LOCATION:"+escape(e.Location))
+	}
+	if e.URL != "" {
+		lines = append(lines, "URL:"+e.URL)
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+	for _, line := range lines {
+		b.WriteString(fold(line))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// escape quotes the characters RFC 5545 reserves in TEXT values
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// fold splits lines longer than 75 octets, continuing them on lines that
+// start with a space, without cutting a UTF-8 sequence in half
+func fold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space.
+		width = limit - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/yesetoda/Sera_Ale/internal/logging"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
 )
 
 // AuthMiddleware godoc
@@ -36,6 +39,9 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 		}
 		c.Set("user_id", claims["user_id"])
 		c.Set("role", claims["role"])
+		userID, _ := claims["user_id"].(string)
+		role, _ := claims["role"].(string)
+		c.Request = c.Request.WithContext(logging.WithUser(c.Request.Context(), userID, role))
 		c.Next()
 	}
 }
@@ -43,7 +49,7 @@ func AuthMiddleware(secret string) gin.HandlerFunc {
 // RequireRole godoc
 // @Summary Role-based Access Middleware
 // @Description Allows access only to users with the specified role name
-// @Param role path string true "Role (applicant or company)"
+// @Param role path string true "Role (applicant, company or admin)"
 // @Failure 403 {object} map[string]interface{} "Forbidden"
 // @Router /protected/{role} [get]
 func RequireRole(role string) gin.HandlerFunc {
@@ -55,3 +61,27 @@ func RequireRole(role string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequirePermission allows access to users whose role holds perm for at
+// least some resources. Ownership is checked later by the app layer.
+func RequirePermission(perm policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !policy.Allows(c.GetString("role"), perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "message": "Forbidden: insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireActiveAccount rejects tokens that belong to suspended or deleted
+// accounts. It must run after AuthMiddleware.
+func RequireActiveAccount(check func(ctx context.Context, userID string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := check(c.Request.Context(), c.GetString("user_id")); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "message": "Forbidden: account is not active"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+// Package moderation decides whether a newly posted or edited job must be
+// held for review before it becomes public.
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// Rules configures the auto-hold checks. Zero values disable a check.
+type Rules struct {
+	// BannedKeywords holds jobs whose title or description mention any of
+	// these words (case-insensitive).
+	BannedKeywords []string
+	// HoldLinks holds jobs whose description contains a URL.
+	HoldLinks bool
+	// NewAccountAge and NewAccountMaxJobs hold jobs from accounts younger
+	// than NewAccountAge once they have posted NewAccountMaxJobs jobs.
+	NewAccountAge     time.Duration
+	NewAccountMaxJobs int64
+	// ReportThreshold holds a published job once it has this many unresolved
+	// reports.
+	ReportThreshold int64
+}
+
+// Submission is what the rules look at for a job being posted or edited
+type Submission struct {
+	Title       string
+	Description string
+	// AccountAge is how long the posting company has existed.
+	AccountAge time.Duration
+	// RecentJobs counts jobs the company already posted within NewAccountAge.
+	RecentJobs int64
+}
+
+// Check returns the reasons the submission must be held, or nil if it can be
+// published straight away.
+func (r Rules) Check(s Submission) []string {
+	var reasons []string
+	text := strings.ToLower(s.Title + " " + s.Description)
+	for _, kw := range r.BannedKeywords {
+		if kw != "" && strings.Contains(text, strings.ToLower(kw)) {
+			reasons = append(reasons, fmt.Sprintf("contains banned keyword %q", kw))
+		}
+	}
+	if r.HoldLinks && linkPattern.MatchString(s.Description) {
+		reasons = append(reasons, "description contains a link")
+	}
+	if r.NewAccountAge > 0 && r.NewAccountMaxJobs > 0 &&
+		s.AccountAge < r.NewAccountAge && s.RecentJobs >= r.NewAccountMaxJobs {
+		reasons = append(reasons, "new account posting many jobs")
+	}
+	return reasons
+}
@@ -0,0 +1,133 @@
+// Package policy is the single place that decides who may do what. Handlers
+// and apps describe the action as a Permission plus the Resource it targets
+// and ask Can; they never compare roles or owner IDs themselves.
+package policy
+
+import (
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+type Permission string
+
+const (
+	JobCreate     Permission = "job:create"
+	JobUpdate     Permission = "job:update"
+	JobDelete     Permission = "job:delete"
+	JobSearch     Permission = "job:search"
+	JobViewHidden Permission = "job:view_hidden"
+	JobModerate   Permission = "job:moderate"
+	JobReport     Permission = "job:report"
+	JobBookmark   Permission = "job:bookmark"
+
+	ApplicationCreate   Permission = "application:create"
+	ApplicationRead     Permission = "application:read"
+	ApplicationStatus   Permission = "application:status"
+	ApplicationWithdraw Permission = "application:withdraw"
+	ApplicationMessage  Permission = "application:message"
+	ApplicationReview   Permission = "application:review"
+
+	WebhookManage     Permission = "webhook:manage"
+	NotificationRead  Permission = "notification:read"
+	SavedSearchManage Permission = "saved_search:manage"
+
+	InterviewManage  Permission = "interview:manage"
+	InterviewRespond Permission = "interview:respond"
+	InterviewRead    Permission = "interview:read"
+
+	UserManage Permission = "user:manage"
+	StatsRead  Permission = "stats:read"
+)
+
+// Scope says which resources a grant covers
+type Scope uint8
+
+const (
+	// Owner covers resources owned by the actor: their jobs, the
+	// applications submitted to those jobs, the interviews scheduled on
+	// them and their webhooks.
+	Owner Scope = 1 << iota
+	// Subject covers resources about the actor: their own applications,
+	// notifications, saved searches, bookmarks and interviews.
+	Subject
+	// Any covers every resource.
+	Any
+)
+
+// Actor is the authenticated caller. The zero Actor is an anonymous visitor.
+type Actor struct {
+	ID   string
+	Role string
+}
+
+// Resource identifies who a targeted object belongs to. OwnerID is the
+// company that owns the job (or the job an application was sent to);
+// SubjectID is the applicant an application belongs to. Unused IDs stay empty.
+type Resource struct {
+	OwnerID   string
+	SubjectID string
+}
+
+// rules is the full permission table: role -> permission -> scope. Anything
+// not listed is denied.
+var rules = map[string]map[Permission]Scope{
+	domain.RoleCompany: {
+		JobCreate:          Any,
+		JobUpdate:          Owner,
+		JobDelete:          Owner,
+		JobViewHidden:      Owner,
+		ApplicationRead:    Owner,
+		ApplicationStatus:  Owner,
+		ApplicationMessage: Owner,
+		ApplicationReview:  Owner,
+		WebhookManage:      Owner,
+		NotificationRead:   Subject,
+		InterviewManage:    Owner,
+		InterviewRead:      Owner,
+	},
+	domain.RoleApplicant: {
+		JobSearch:           Any,
+		JobReport:           Any,
+		JobBookmark:         Subject,
+		ApplicationCreate:   Any,
+		ApplicationRead:     Subject,
+		ApplicationWithdraw: Subject,
+		ApplicationMessage:  Subject,
+		NotificationRead:    Subject,
+		SavedSearchManage:   Subject,
+		InterviewRespond:    Subject,
+		InterviewRead:       Subject,
+	},
+	domain.RoleAdmin: {
+		JobSearch:        Any,
+		JobViewHidden:    Any,
+		JobModerate:      Any,
+		ApplicationRead:  Any,
+		UserManage:       Any,
+		StatsRead:        Any,
+		NotificationRead: Subject,
+		InterviewRead:    Any,
+	},
+}
+
+// Allows reports whether the role holds perm for at least some resources.
+// It is meant for coarse route gating; resource checks still go through Can.
+func Allows(role string, perm Permission) bool {
+	return rules[role][perm] != 0
+}
+
+// Can reports whether actor may perform perm on res
+func Can(actor Actor, perm Permission, res Resource) bool {
+	if actor.ID == "" {
+		return false
+	}
+	scope := rules[actor.Role][perm]
+	switch {
+	case scope&Any != 0:
+		return true
+	case scope&Owner != 0 && res.OwnerID == actor.ID:
+		return true
+	case scope&Subject != 0 && res.SubjectID == actor.ID:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+// grants is what each role may do with one permission; a zero Scope denies
+type grants struct {
+	company, applicant, admin Scope
+}
+
+// table pins down who can do what. It is written out by hand rather than
+// derived from rules, so a change to rules has to be made here as well.
+var table = map[Permission]grants{
+	JobCreate:     {company: Any},
+	JobUpdate:     {company: Owner},
+	JobDelete:     {company: Owner},
+	JobSearch:     {applicant: Any, admin: Any},
+	JobViewHidden: {company: Owner, admin: Any},
+	JobModerate:   {admin: Any},
+	JobReport:     {applicant: Any},
+	JobBookmark:   {applicant: Subject},
+
+	ApplicationCreate:   {applicant: Any},
+	ApplicationRead:     {company: Owner, applicant: Subject, admin: Any},
+	ApplicationStatus:   {company: Owner},
+	ApplicationWithdraw: {applicant: Subject},
+	ApplicationMessage:  {company: Owner, applicant: Subject},
+	ApplicationReview:   {company: Owner},
+
+	WebhookManage:     {company: Owner},
+	NotificationRead:  {company: Subject, applicant: Subject, admin: Subject},
+	SavedSearchManage: {applicant: Subject},
+
+	InterviewManage:  {company: Owner},
+	InterviewRespond: {applicant: Subject},
+	InterviewRead:    {company: Owner, applicant: Subject, admin: Any},
+
+	UserManage: {admin: Any},
+	StatsRead:  {admin: Any},
+}
+
+func (g grants) scope(role string) Scope {
+	switch role {
+	case domain.RoleCompany:
+		return g.company
+	case domain.RoleApplicant:
+		return g.applicant
+	case domain.RoleAdmin:
+		return g.admin
+	}
+	return 0
+}
+
+func TestTableCoversEveryRule(t *testing.T) {
+	for role, perms := range rules {
+		for perm := range perms {
+			if _, ok := table[perm]; !ok {
+				t.Errorf("rules grants %s to %s but the test table does not list it", perm, role)
+			}
+		}
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	const actorID = "actor"
+	roles := []string{domain.RoleCompany, domain.RoleApplicant, domain.RoleAdmin, "unknown", ""}
+	resources := []struct {
+		name  string
+		res   Resource
+		scope Scope
+	}{
+		{"owner", Resource{OwnerID: actorID}, Owner},
+		{"subject", Resource{SubjectID: actorID}, Subject},
+		{"other", Resource{OwnerID: "someone", SubjectID: "someone"}, 0},
+	}
+	for perm, g := range table {
+		for _, role := range roles {
+			scope := g.scope(role)
+			if got, want := Allows(role, perm), scope != 0; got != want {
+				t.Errorf("Allows(%q, %s) = %v, want %v", role, perm, got, want)
+			}
+			for _, r := range resources {
+				want := scope&Any != 0 || scope&r.scope != 0
+				if got := Can(Actor{ID: actorID, Role: role}, perm, r.res); got != want {
+					t.Errorf("Can(%q, %s, %s) = %v, want %v", role, perm, r.name, got, want)
+				}
+				if Can(Actor{Role: role}, perm, r.res) {
+					t.Errorf("Can(anonymous %q, %s, %s) = true, want false", role, perm, r.name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package recommend ranks open jobs for an applicant. Scoring is a fixed set
+// of weighted rules over the applicant's profile, saved searches and past
+// applications, so the same input always gives the same ranking and every
+// point can be traced back to a reason.
+package recommend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+)
+
+// Points awarded per signal
+const (
+	savedSearchPoints  = 5
+	locationPoints     = 4
+	skillTitlePoints   = 3
+	skillTextPoints    = 1
+	appliedWordPoints  = 2
+	maxAppliedWords    = 3
+	sameCompanyPoints  = 2
+	maxReasonsInResult = 2
+)
+
+// Signals is everything known about the applicant
+type Signals struct {
+	Skills   []string
+	Location string
+	Searches []domain.SavedSearch
+	// Applied holds jobs the applicant applied to, most recent first.
+	Applied []domain.Job
+}
+
+// Recommendation is a ranked job with the reasons it was picked
+type Recommendation struct {
+	Job    *domain.Job `json:"job"`
+	Score  int         `json:"score"`
+	Reason string      `json:"reason"`
+}
+
+type reason struct {
+	points int
+	text   string
+}
+
+// Rank scores candidates and returns up to limit of them, best first. Ties
+// go to the newer job. Jobs that match no signal are kept at the end, newest
+// first, so an applicant without history still gets suggestions.
+func Rank(s Signals, candidates []domain.Job, limit int) []Recommendation {
+	history := newHistory(s.Applied)
+	type scored struct {
+		rec     Recommendation
+		reasons []reason
+	}
+	all := make([]scored, 0, len(candidates))
+	for i := range candidates {
+		job := &candidates[i]
+		reasons := score(s, history, job)
+		total := 0
+		for _, r := range reasons {
+			total += r.points
+		}
+		all = append(all, scored{rec: Recommendation{Job: job, Score: total}, reasons: reasons})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i].rec, all[j].rec
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Job.CreatedAt.Equal(b.Job.CreatedAt) {
+			return a.Job.CreatedAt.After(b.Job.CreatedAt)
+		}
+		return a.Job.ID.String() < b.Job.ID.String()
+	})
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	recs := make([]Recommendation, 0, len(all))
+	for _, sc := range all {
+		sc.rec.Reason = summarize(sc.reasons)
+		recs = append(recs, sc.rec)
+	}
+	return recs
+}
+
+func score(s Signals, h history, job *domain.Job) []reason {
+	var reasons []reason
+	title := strings.ToLower(job.Title)
+	location := strings.ToLower(job.Location)
+	for i := range s.Searches {
+		if matchesSearch(&s.Searches[i], title, location) {
+			reasons = append(reasons, reason{savedSearchPoints, fmt.Sprintf("Matches your saved search %q", s.Searches[i].Name)})
+			break
+		}
+	}
+	if s.Location != "" && strings.Contains(location, strings.ToLower(s.Location)) {
+		reasons = append(reasons, reason{locationPoints, fmt.Sprintf("Located in %s", s.Location)})
+	}
+	var inTitle, inText []string
+	description := strings.ToLower(job.Description)
+	for _, skill := range s.Skills {
+		lower := strings.ToLower(skill)
+		switch {
+		case containsWord(title, lower):
+			inTitle = append(inTitle, skill)
+		case containsWord(description, lower):
+			inText = append(inText, skill)
+		}
+	}
+	if len(inTitle)+len(inText) > 0 {
+		points := len(inTitle)*skillTitlePoints + len(inText)*skillTextPoints
+		reasons = append(reasons, reason{points, "Matches your skills: " + strings.Join(append(inTitle, inText...), ", ")})
+	}
+	if shared, like := h.similarTitle(title); shared > 0 {
+		reasons = append(reasons, reason{min(shared, maxAppliedWords) * appliedWordPoints, fmt.Sprintf("Similar to %s, which you applied to", like)})
+	}
+	if h.companies[job.CreatedBy] {
+		reasons = append(reasons, reason{sameCompanyPoints, "From a company you applied to before"})
+	}
+	return reasons
+}
+
+// matchesSearch applies the title and location filters of a saved search.
+// Searches filtering only on company name are skipped, as candidates do not
+// carry it.
+func matchesSearch(search *domain.SavedSearch, title, location string) bool {
+	if search.Title == "" && search.Location == "" {
+		return false
+	}
+	if search.Title != "" && !strings.Contains(title, strings.ToLower(search.Title)) {
+		return false
+	}
+	return search.Location == "" || strings.Contains(location, strings.ToLower(search.Location))
+}
+
+// summarize joins the strongest reasons into one short sentence
+func summarize(reasons []reason) string {
+	if len(reasons) == 0 {
+		return "Recently posted"
+	}
+	sorted := append([]reason(nil), reasons...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].points > sorted[j].points })
+	if len(sorted) > maxReasonsInResult {
+		sorted = sorted[:maxReasonsInResult]
+	}
+	texts := make([]string, 0, len(sorted))
+	for _, r := range sorted {
+		texts = append(texts, r.text)
+	}
+	return strings.Join(texts, "; ")
+}
+
+// history indexes the jobs an applicant applied to
+type history struct {
+	titles    []string
+	words     []map[string]bool
+	companies map[uuid.UUID]bool
+}
+
+func newHistory(applied []domain.Job) history {
+	h := history{companies: map[uuid.UUID]bool{}}
+	for _, job := range applied {
+		h.titles = append(h.titles, job.Title)
+		h.words = append(h.words, titleWords(job.Title))
+		h.companies[job.CreatedBy] = true
+	}
+	return h
+}
+
+// similarTitle returns how many title words the closest applied-to job
+// shares with title, and that job's title
+func (h history) similarTitle(title string) (int, string) {
+	best, like := 0, ""
+	words := titleWords(title)
+	for i, applied := range h.words {
+		shared := 0
+		for w := range words {
+			if applied[w] {
+				shared++
+			}
+		}
+		if shared > best {
+			best, like = shared, h.titles[i]
+		}
+	}
+	return best, like
+}
+
+// stopWords are title words too common to say anything about a job
+var stopWords = map[string]bool{
+	"and": true, "the": true, "for": true, "with": true, "senior": true, "junior": true,
+	"lead": true, "mid": true, "level": true, "remote": true, "full": true, "part": true, "time": true,
+}
+
+func titleWords(title string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(title), isSeparator) {
+		if len(w) >= 3 && !stopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// containsWord reports whether word occurs in text on word boundaries, so
+// "go" does not match "google"
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	for start := 0; ; {
+		i := strings.Index(text[start:], word)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(word)
+		before := i == 0 || isSeparator(rune(text[i-1]))
+		if before && endsWord(text, end) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// endsWord reports whether a word ending at i is complete. A full stop only
+// ends it when it ends the sentence, so "go." matches go but "go.dev" does not.
+func endsWord(text string, i int) bool {
+	if i == len(text) {
+		return true
+	}
+	if text[i] == '.' {
+		return i+1 == len(text) || isSeparator(rune(text[i+1]))
+	}
+	return isSeparator(rune(text[i]))
+}
+
+// isSeparator splits words but keeps characters used in skill names such as
+// c++, c# and node.js
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#.", r)
+}
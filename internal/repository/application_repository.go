@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/yesetoda/Sera_Ale/internal/domain"
 
 	"gorm.io/gorm"
@@ -10,11 +11,14 @@ import (
 
 type ApplicationRepository interface {
 	Create(ctx context.Context, app *domain.Application) error
-	FindByApplicant(ctx context.Context, applicantID string, page, size int) ([]domain.Application, int64, error)
-	FindByJob(ctx context.Context, jobID string, page, size int) ([]domain.Application, int64, error)
+	FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.Application, domain.PageInfo, error)
+	FindByJob(ctx context.Context, jobID string, tags []string, q domain.PageQuery) ([]domain.Application, domain.PageInfo, error)
 	FindByID(ctx context.Context, id string) (*domain.Application, error)
 	UpdateStatus(ctx context.Context, id string, status domain.ApplicationStatus) error
 	FindByApplicantAndJob(ctx context.Context, applicantID, jobID string) (*domain.Application, error)
+	FindByApplicantAndJobs(ctx context.Context, applicantID string, jobIDs []uuid.UUID) ([]domain.Application, error)
+	CountByStatus(ctx context.Context) (map[domain.ApplicationStatus]int64, error)
+	FindApplicantRows(ctx context.Context, jobID string, after *domain.Cursor, limit int) ([]domain.ApplicantRow, error)
 }
 
 type applicationRepository struct {
@@ -26,30 +30,27 @@ func NewApplicationRepository(db *gorm.DB) ApplicationRepository {
 }
 
 func (r *applicationRepository) Create(ctx context.Context, app *domain.Application) error {
-	return r.db.WithContext(ctx).Create(app).Error
+	return conn(ctx, r.db).Create(app).Error
 }
 
-func (r *applicationRepository) FindByApplicant(ctx context.Context, applicantID string, page, size int) ([]domain.Application, int64, error) {
-	var apps []domain.Application
-	var total int64
-	db := r.db.WithContext(ctx).Where("applicant_id = ?", applicantID)
-	db.Model(&domain.Application{}).Count(&total)
-	err := db.Offset((page - 1) * size).Limit(size).Find(&apps).Error
-	return apps, total, err
+func (r *applicationRepository) FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.Application, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Application{}).Where("applicant_id = ?", applicantID)
+	return paginate(db, q, "applied_at", applicationCursor)
 }
 
-func (r *applicationRepository) FindByJob(ctx context.Context, jobID string, page, size int) ([]domain.Application, int64, error) {
-	var apps []domain.Application
-	var total int64
-	db := r.db.WithContext(ctx).Where("job_id = ?", jobID)
-	db.Model(&domain.Application{}).Count(&total)
-	err := db.Offset((page - 1) * size).Limit(size).Find(&apps).Error
-	return apps, total, err
+// FindByJob lists a job's applications. With tags, only applications
+// carrying every one of them are returned.
+func (r *applicationRepository) FindByJob(ctx context.Context, jobID string, tags []string, q domain.PageQuery) ([]domain.Application, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Application{}).Where("job_id = ?", jobID)
+	for _, tag := range tags {
+		db = db.Where("EXISTS (SELECT 1 FROM application_tags WHERE application_tags.application_id = applications.id AND application_tags.tag = ?)", tag)
+	}
+	return paginate(db, q, "applied_at", applicationCursor)
 }
 
 func (r *applicationRepository) FindByID(ctx context.Context, id string) (*domain.Application, error) {
 	var app domain.Application
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&app).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&app).Error
 	if err != nil {
 		return nil, err
 	}
@@ -57,14 +58,58 @@ func (r *applicationRepository) FindByID(ctx context.Context, id string) (*domai
 }
 
 func (r *applicationRepository) UpdateStatus(ctx context.Context, id string, status domain.ApplicationStatus) error {
-	return r.db.WithContext(ctx).Model(&domain.Application{}).Where("id = ?", id).Update("status", status).Error
+	return conn(ctx, r.db).Model(&domain.Application{}).Where("id = ?", id).Update("status", status).Error
 }
 
 func (r *applicationRepository) FindByApplicantAndJob(ctx context.Context, applicantID, jobID string) (*domain.Application, error) {
 	var app domain.Application
-	err := r.db.WithContext(ctx).Where("applicant_id = ? AND job_id = ?", applicantID, jobID).First(&app).Error
+	err := conn(ctx, r.db).Where("applicant_id = ? AND job_id = ?", applicantID, jobID).First(&app).Error
 	if err != nil {
 		return nil, err
 	}
 	return &app, nil
 }
+
+// FindByApplicantAndJobs is FindByApplicantAndJob for many jobs in one query;
+// jobs the applicant did not apply to are simply absent from the result
+func (r *applicationRepository) FindByApplicantAndJobs(ctx context.Context, applicantID string, jobIDs []uuid.UUID) ([]domain.Application, error) {
+	var apps []domain.Application
+	if len(jobIDs) == 0 {
+		return apps, nil
+	}
+	err := conn(ctx, r.db).Where("applicant_id = ? AND job_id IN ?", applicantID, jobIDs).Find(&apps).Error
+	return apps, err
+}
+
+func (r *applicationRepository) CountByStatus(ctx context.Context) (map[domain.ApplicationStatus]int64, error) {
+	var rows []struct {
+		Status domain.ApplicationStatus
+		Count  int64
+	}
+	err := conn(ctx, r.db).Model(&domain.Application{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[domain.ApplicationStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindApplicantRows returns up to limit of a job's applications with the
+// applicant's name and email, oldest first, starting after the given
+// (applied_at, id) position. Exports walk a job's applications with it one
+// batch at a time.
+func (r *applicationRepository) FindApplicantRows(ctx context.Context, jobID string, after *domain.Cursor, limit int) ([]domain.ApplicantRow, error) {
+	var rows []domain.ApplicantRow
+	db := conn(ctx, r.db).Model(&domain.Application{}).
+		Select("applications.id AS application_id, users.name, users.email, applications.status, applications.applied_at, applications.cover_letter, applications.resume_link").
+		Joins("JOIN users ON users.id = applications.applicant_id").
+		Where("applications.job_id = ?", jobID)
+	if after != nil {
+		db = db.Where("(applications.applied_at, applications.id) > (?, ?)", after.CreatedAt, after.ID)
+	}
+	err := db.Order("applications.applied_at ASC, applications.id ASC").Limit(limit).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository interface {
+	Create(ctx context.Context, bookmark *domain.Bookmark) error
+	Delete(ctx context.Context, applicantID, jobID string) error
+	FindByApplicantAndJob(ctx context.Context, applicantID, jobID string) (*domain.Bookmark, error)
+	FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.Bookmark, domain.PageInfo, error)
+}
+
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+// Create saves the bookmark; saving a job twice keeps the first bookmark
+func (r *bookmarkRepository) Create(ctx context.Context, bookmark *domain.Bookmark) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "applicant_id"}, {Name: "job_id"}},
+		DoNothing: true,
+	}).Create(bookmark).Error
+}
+
+func (r *bookmarkRepository) Delete(ctx context.Context, applicantID, jobID string) error {
+	return conn(ctx, r.db).Delete(&domain.Bookmark{}, "applicant_id = ? AND job_id = ?", applicantID, jobID).Error
+}
+
+func (r *bookmarkRepository) FindByApplicantAndJob(ctx context.Context, applicantID, jobID string) (*domain.Bookmark, error) {
+	var bookmark domain.Bookmark
+	err := conn(ctx, r.db).Where("applicant_id = ? AND job_id = ?", applicantID, jobID).First(&bookmark).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bookmark, nil
+}
+
+// FindByApplicant lists the applicant's bookmarks, newest first
+func (r *bookmarkRepository) FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.Bookmark, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Bookmark{}).Where("applicant_id = ?", applicantID)
+	return paginate(db, q, "created_at", bookmarkCursor)
+}
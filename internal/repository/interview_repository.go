@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InterviewRepository interface {
+	Create(ctx context.Context, interview *domain.Interview) error
+	Update(ctx context.Context, interview *domain.Interview) error
+	FindByID(ctx context.Context, id string) (*domain.Interview, error)
+	FindByApplication(ctx context.Context, applicationID string) ([]domain.Interview, error)
+	FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.Interview, domain.PageInfo, error)
+	ClaimDueReminders(ctx context.Context, now time.Time, lead time.Duration, limit int) ([]domain.Interview, error)
+}
+
+type interviewRepository struct {
+	db *gorm.DB
+}
+
+func NewInterviewRepository(db *gorm.DB) InterviewRepository {
+	return &interviewRepository{db: db}
+}
+
+func (r *interviewRepository) Create(ctx context.Context, interview *domain.Interview) error {
+	return conn(ctx, r.db).Create(interview).Error
+}
+
+func (r *interviewRepository) Update(ctx context.Context, interview *domain.Interview) error {
+	return conn(ctx, r.db).Save(interview).Error
+}
+
+func (r *interviewRepository) FindByID(ctx context.Context, id string) (*domain.Interview, error) {
+	var interview domain.Interview
+	err := conn(ctx, r.db).Where("id = ?", id).First(&interview).Error
+	if err != nil {
+		return nil, err
+	}
+	return &interview, nil
+}
+
+func (r *interviewRepository) FindByApplication(ctx context.Context, applicationID string) ([]domain.Interview, error) {
+	var interviews []domain.Interview
+	err := conn(ctx, r.db).Where("application_id = ?", applicationID).Order("created_at DESC").Find(&interviews).Error
+	return interviews, err
+}
+
+func (r *interviewRepository) FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.Interview, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Interview{}).Where("applicant_id = ?", applicantID)
+	return paginate(db, q, "created_at", interviewCursor)
+}
+
+// ClaimDueReminders returns up to limit scheduled interviews starting within
+// lead that have not been reminded of yet, and marks them reminded. A
+// reminder lost to a crash is not retried; a duplicate would be worse.
+func (r *interviewRepository) ClaimDueReminders(ctx context.Context, now time.Time, lead time.Duration, limit int) ([]domain.Interview, error) {
+	var interviews []domain.Interview
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND reminder_sent_at IS NULL AND starts_at > ? AND starts_at <= ?", domain.InterviewScheduled, now, now.Add(lead)).
+			Order("starts_at ASC").Limit(limit).Find(&interviews).Error
+		if err != nil || len(interviews) == 0 {
+			return err
+		}
+		ids := make([]uuid.UUID, 0, len(interviews))
+		for _, i := range interviews {
+			ids = append(ids, i.ID)
+		}
+		return tx.Model(&domain.Interview{}).Where("id IN ?", ids).UpdateColumn("reminder_sent_at", now).Error
+	})
+	return interviews, err
+}
@@ -2,18 +2,27 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/yesetoda/Sera_Ale/internal/domain"
 	"gorm.io/gorm"
 )
 
 type JobRepository interface {
 	Create(ctx context.Context, job *domain.Job) error
+	CreateMany(ctx context.Context, jobs []domain.Job) error
 	Update(ctx context.Context, job *domain.Job) error
-	Delete(ctx context.Context, id string) error
 	FindByID(ctx context.Context, id string) (*domain.Job, error)
-	FindByCompany(ctx context.Context, companyID string, page, size int) ([]domain.Job, int64, error)
-	Search(ctx context.Context, filters map[string]interface{}, page, size int) ([]domain.Job, int64, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Job, error)
+	FindByCompany(ctx context.Context, companyID string, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error)
+	Search(ctx context.Context, filters map[string]interface{}, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error)
+	UpdateStatus(ctx context.Context, id string, status domain.JobStatus, holdReason string) error
+	CountByStatus(ctx context.Context) (map[domain.JobStatus]int64, error)
+	CountByCompanySince(ctx context.Context, companyID string, since time.Time) (int64, error)
+	FindForModeration(ctx context.Context, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error)
+	FindOpenForApplicant(ctx context.Context, applicantID string, limit int) ([]domain.Job, error)
 }
 
 type jobRepository struct {
@@ -25,47 +34,118 @@ func NewJobRepository(db *gorm.DB) JobRepository {
 }
 
 func (r *jobRepository) Create(ctx context.Context, job *domain.Job) error {
-	return r.db.WithContext(ctx).Create(job).Error
+	return conn(ctx, r.db).Create(job).Error
 }
 
-func (r *jobRepository) Update(ctx context.Context, job *domain.Job) error {
-	return r.db.WithContext(ctx).Save(job).Error
+// CreateMany inserts jobs in one transaction, so either all of them are
+// stored or none
+func (r *jobRepository) CreateMany(ctx context.Context, jobs []domain.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&jobs, 100).Error
+	})
 }
 
-func (r *jobRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&domain.Job{}, "id = ?", id).Error
+func (r *jobRepository) Update(ctx context.Context, job *domain.Job) error {
+	return conn(ctx, r.db).Save(job).Error
 }
 
 func (r *jobRepository) FindByID(ctx context.Context, id string) (*domain.Job, error) {
 	var job domain.Job
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&job).Error
 	if err != nil {
 		return nil, err
 	}
 	return &job, nil
 }
 
-func (r *jobRepository) FindByCompany(ctx context.Context, companyID string, page, size int) ([]domain.Job, int64, error) {
+func (r *jobRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Job, error) {
 	var jobs []domain.Job
-	var total int64
-	db := r.db.WithContext(ctx).Where("created_by = ?", companyID)
-	db.Model(&domain.Job{}).Count(&total)
-	err := db.Offset((page - 1) * size).Limit(size).Find(&jobs).Error
-	return jobs, total, err
+	if len(ids) == 0 {
+		return jobs, nil
+	}
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&jobs).Error
+	return jobs, err
 }
 
-func (r *jobRepository) Search(ctx context.Context, filters map[string]interface{}, page, size int) ([]domain.Job, int64, error) {
-	var jobs []domain.Job
-	var total int64
-	db := r.db.WithContext(ctx).Model(&domain.Job{})
+// FindByCompany lists a company's published jobs, newest first
+func (r *jobRepository) FindByCompany(ctx context.Context, companyID string, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Job{}).Where("created_by = ? AND status = ?", companyID, domain.JobStatusPublished)
+	return paginate(db, q, "created_at", jobCursor)
+}
+
+func (r *jobRepository) Search(ctx context.Context, filters map[string]interface{}, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Job{}).Where("status = ?", domain.JobStatusPublished)
+	return paginate(filterJobs(db, filters), q, "created_at", jobCursor)
+}
+
+// filterJobs applies the title, location, company_name, company_id and
+// posted_since search filters. Saved searches reuse it so their alerts
+// match what the search returns.
+func filterJobs(db *gorm.DB, filters map[string]interface{}) *gorm.DB {
 	if title, ok := filters["title"]; ok {
-		db = db.Where("LOWER(title) LIKE ?", "%"+title.(string)+"%")
+		db = db.Where("LOWER(title) LIKE ?", "%"+strings.ToLower(title.(string))+"%")
 	}
 	if location, ok := filters["location"]; ok {
 		db = db.Where("location LIKE ?", "%"+location.(string)+"%")
 	}
-	// companyName filter requires a join, implement in service layer if needed
-	db.Count(&total)
-	err := db.Offset((page - 1) * size).Limit(size).Find(&jobs).Error
-	return jobs, total, err
+	if company, ok := filters["company_name"]; ok {
+		companies := db.Session(&gorm.Session{NewDB: true}).Model(&domain.User{}).Select("id").
+			Where("LOWER(name) LIKE ?", "%"+strings.ToLower(company.(string))+"%")
+		db = db.Where("created_by IN (?)", companies)
+	}
+	if companyID, ok := filters["company_id"]; ok {
+		db = db.Where("created_by = ?", companyID)
+	}
+	if since, ok := filters["posted_since"]; ok {
+		db = db.Where("created_at >= ?", since)
+	}
+	return db
+}
+
+func (r *jobRepository) UpdateStatus(ctx context.Context, id string, status domain.JobStatus, holdReason string) error {
+	return conn(ctx, r.db).Model(&domain.Job{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "hold_reason": holdReason}).Error
+}
+
+func (r *jobRepository) CountByStatus(ctx context.Context) (map[domain.JobStatus]int64, error) {
+	var rows []struct {
+		Status domain.JobStatus
+		Count  int64
+	}
+	err := conn(ctx, r.db).Model(&domain.Job{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[domain.JobStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *jobRepository) CountByCompanySince(ctx context.Context, companyID string, since time.Time) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.Job{}).Where("created_by = ? AND created_at >= ?", companyID, since).Count(&total).Error
+	return total, err
+}
+
+// FindForModeration lists held jobs and published jobs with unresolved reports
+func (r *jobRepository) FindForModeration(ctx context.Context, q domain.PageQuery) ([]domain.Job, domain.PageInfo, error) {
+	reported := r.db.Model(&domain.JobReport{}).Select("job_id").Where("resolved = ?", false)
+	db := conn(ctx, r.db).Model(&domain.Job{}).
+		Where("status = ? OR (status = ? AND id IN (?))", domain.JobStatusHeld, domain.JobStatusPublished, reported)
+	return paginate(db, q, "created_at", jobCursor)
+}
+
+// FindOpenForApplicant returns up to limit published jobs the applicant has
+// not applied to, newest first
+func (r *jobRepository) FindOpenForApplicant(ctx context.Context, applicantID string, limit int) ([]domain.Job, error) {
+	applied := r.db.Model(&domain.Application{}).Select("job_id").Where("applicant_id = ?", applicantID)
+	var jobs []domain.Job
+	err := conn(ctx, r.db).Where("status = ? AND id NOT IN (?)", domain.JobStatusPublished, applied).
+		Order("created_at DESC").Order("id DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
 }
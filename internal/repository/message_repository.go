@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+)
+
+type MessageRepository interface {
+	Create(ctx context.Context, message *domain.Message) error
+	FindByApplication(ctx context.Context, applicationID string, q domain.PageQuery) ([]domain.Message, domain.PageInfo, error)
+	MarkRead(ctx context.Context, applicationID, recipientID string) error
+	CountUnread(ctx context.Context, recipientID string) ([]domain.UnreadCount, error)
+}
+
+type messageRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageRepository(db *gorm.DB) MessageRepository {
+	return &messageRepository{db: db}
+}
+
+func (r *messageRepository) Create(ctx context.Context, message *domain.Message) error {
+	return conn(ctx, r.db).Create(message).Error
+}
+
+func (r *messageRepository) FindByApplication(ctx context.Context, applicationID string, q domain.PageQuery) ([]domain.Message, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Message{}).Where("application_id = ?", applicationID)
+	return paginate(db, q, "created_at", messageCursor)
+}
+
+// MarkRead marks every message recipientID received in an application's
+// thread as read
+func (r *messageRepository) MarkRead(ctx context.Context, applicationID, recipientID string) error {
+	return conn(ctx, r.db).Model(&domain.Message{}).
+		Where("application_id = ? AND recipient_id = ? AND read_at IS NULL", applicationID, recipientID).
+		Update("read_at", time.Now()).Error
+}
+
+// CountUnread counts unread messages per thread. Threads of withdrawn
+// applications and of deleted or removed jobs are hidden and left out.
+func (r *messageRepository) CountUnread(ctx context.Context, recipientID string) ([]domain.UnreadCount, error) {
+	var counts []domain.UnreadCount
+	err := conn(ctx, r.db).Model(&domain.Message{}).
+		Select("messages.application_id, COUNT(*) AS unread").
+		Joins("JOIN applications ON applications.id = messages.application_id").
+		Joins("JOIN jobs ON jobs.id = applications.job_id").
+		Where("messages.recipient_id = ? AND messages.read_at IS NULL", recipientID).
+		Where("applications.status <> ? AND jobs.status <> ?", domain.StatusWithdrawn, domain.JobStatusRemoved).
+		Group("messages.application_id").
+		Order("messages.application_id").
+		Scan(&counts).Error
+	return counts, err
+}
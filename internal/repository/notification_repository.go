@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *domain.Notification) error
+	FindByID(ctx context.Context, id string) (*domain.Notification, error)
+	FindInbox(ctx context.Context, userID string, unreadOnly bool, q domain.PageQuery) ([]domain.Notification, domain.PageInfo, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	MarkRead(ctx context.Context, id string) error
+	MarkAllRead(ctx context.Context, userID string) error
+
+	FindPreferences(ctx context.Context, userID string) ([]domain.NotificationPreference, error)
+	SavePreferences(ctx context.Context, prefs []domain.NotificationPreference) error
+
+	ClaimPendingEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Notification, error)
+	RecordEmail(ctx context.Context, notification *domain.Notification) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	return conn(ctx, r.db).Create(notification).Error
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id string) (*domain.Notification, error) {
+	var notification domain.Notification
+	err := conn(ctx, r.db).Where("id = ? AND in_inbox", id).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) FindInbox(ctx context.Context, userID string, unreadOnly bool, q domain.PageQuery) ([]domain.Notification, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Notification{}).Where("user_id = ? AND in_inbox", userID)
+	if unreadOnly {
+		db = db.Where("read_at IS NULL")
+	}
+	return paginate(db, q, "created_at", notificationCursor)
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.Notification{}).
+		Where("user_id = ? AND in_inbox AND read_at IS NULL", userID).Count(&total).Error
+	return total, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Model(&domain.Notification{}).Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", time.Now()).Error
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID string) error {
+	return conn(ctx, r.db).Model(&domain.Notification{}).Where("user_id = ? AND in_inbox AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+func (r *notificationRepository) FindPreferences(ctx context.Context, userID string) ([]domain.NotificationPreference, error) {
+	var prefs []domain.NotificationPreference
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&prefs).Error
+	return prefs, err
+}
+
+// SavePreferences inserts or overwrites each (user, type) preference
+func (r *notificationRepository) SavePreferences(ctx context.Context, prefs []domain.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"in_app", "email"}),
+	}).Create(&prefs).Error
+}
+
+// ClaimPendingEmails returns up to limit notifications whose email is due and
+// hides them from other replicas for lease, like WebhookRepository.ClaimDue
+func (r *notificationRepository) ClaimPendingEmails(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("email_status = ? AND email_next_attempt_at <= ?", domain.EmailPending, now).
+			Order("email_next_attempt_at ASC").Limit(limit).Find(&notifications).Error
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+		ids := make([]uuid.UUID, 0, len(notifications))
+		for _, n := range notifications {
+			ids = append(ids, n.ID)
+		}
+		return tx.Model(&domain.Notification{}).Where("id IN ?", ids).Update("email_next_attempt_at", now.Add(lease)).Error
+	})
+	return notifications, err
+}
+
+// RecordEmail stores the outcome of an email attempt
+func (r *notificationRepository) RecordEmail(ctx context.Context, notification *domain.Notification) error {
+	return conn(ctx, r.db).Model(&domain.Notification{}).Where("id = ?", notification.ID).
+		Updates(map[string]interface{}{
+			"email_status":          notification.EmailStatus,
+			"email_attempts":        notification.EmailAttempts,
+			"email_next_attempt_at": notification.EmailNextAttemptAt,
+		}).Error
+}
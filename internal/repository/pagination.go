@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+)
+
+// paginate returns one page of db ordered newest first on (timeColumn, id).
+// db must already carry its model and filters. Keyset pagination is used when
+// q has a cursor, offset pagination otherwise; cursors are returned in both
+// modes so clients can switch over.
+func paginate[T any](db *gorm.DB, q domain.PageQuery, timeColumn string, key func(*T) domain.Cursor) ([]T, domain.PageInfo, error) {
+	q = q.Normalize()
+	var info domain.PageInfo
+	db = db.Session(&gorm.Session{})
+	if err := db.Count(&info.Total).Error; err != nil {
+		return nil, info, err
+	}
+
+	var cur domain.Cursor
+	tx := db
+	if q.Cursor != "" {
+		c, err := domain.DecodeCursor(q.Cursor)
+		if err != nil {
+			return nil, info, err
+		}
+		cur = c
+		if cur.Backward {
+			tx = tx.Where(fmt.Sprintf("(%s, id) > (?, ?)", timeColumn), cur.CreatedAt, cur.ID).
+				Order(timeColumn + " ASC").Order("id ASC")
+		} else {
+			tx = tx.Where(fmt.Sprintf("(%s, id) < (?, ?)", timeColumn), cur.CreatedAt, cur.ID).
+				Order(timeColumn + " DESC").Order("id DESC")
+		}
+	} else {
+		tx = tx.Order(timeColumn + " DESC").Order("id DESC").Offset(q.Offset())
+	}
+
+	var items []T
+	// Fetch one extra row to learn whether another page exists.
+	if err := tx.Limit(q.Size + 1).Find(&items).Error; err != nil {
+		return nil, info, err
+	}
+	hasMore := len(items) > q.Size
+	if hasMore {
+		items = items[:q.Size]
+	}
+	if cur.Backward {
+		slices.Reverse(items)
+	}
+	if len(items) == 0 {
+		return items, info, nil
+	}
+
+	first, last := key(&items[0]), key(&items[len(items)-1])
+	first.Backward = true
+	switch {
+	case q.Cursor == "":
+		if hasMore {
+			info.NextCursor = last.Encode()
+		}
+		if q.Page > 1 {
+			info.PrevCursor = first.Encode()
+		}
+	case cur.Backward:
+		info.NextCursor = last.Encode()
+		if hasMore {
+			info.PrevCursor = first.Encode()
+		}
+	default:
+		if hasMore {
+			info.NextCursor = last.Encode()
+		}
+		info.PrevCursor = first.Encode()
+	}
+	return items, info, nil
+}
+
+func jobCursor(j *domain.Job) domain.Cursor {
+	return domain.Cursor{CreatedAt: j.CreatedAt, ID: j.ID}
+}
+
+func applicationCursor(a *domain.Application) domain.Cursor {
+	return domain.Cursor{CreatedAt: a.AppliedAt, ID: a.ID}
+}
+
+func userCursor(u *domain.User) domain.Cursor {
+	return domain.Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
+}
+
+func webhookCursor(w *domain.Webhook) domain.Cursor {
+	return domain.Cursor{CreatedAt: w.CreatedAt, ID: w.ID}
+}
+
+func deliveryCursor(d *domain.WebhookDelivery) domain.Cursor {
+	return domain.Cursor{CreatedAt: d.CreatedAt, ID: d.ID}
+}
+
+func notificationCursor(n *domain.Notification) domain.Cursor {
+	return domain.Cursor{CreatedAt: n.CreatedAt, ID: n.ID}
+}
+
+func savedSearchCursor(s *domain.SavedSearch) domain.Cursor {
+	return domain.Cursor{CreatedAt: s.CreatedAt, ID: s.ID}
+}
+
+func bookmarkCursor(b *domain.Bookmark) domain.Cursor {
+	return domain.Cursor{CreatedAt: b.CreatedAt, ID: b.ID}
+}
+
+func interviewCursor(i *domain.Interview) domain.Cursor {
+	return domain.Cursor{CreatedAt: i.CreatedAt, ID: i.ID}
+}
+
+func messageCursor(m *domain.Message) domain.Cursor {
+	return domain.Cursor{CreatedAt: m.CreatedAt, ID: m.ID}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *domain.JobReport) error
+	CountOpenByJob(ctx context.Context, jobID string) (int64, error)
+	FindOpenByJobs(ctx context.Context, jobIDs []uuid.UUID) ([]domain.JobReport, error)
+	ResolveByJob(ctx context.Context, jobID string) error
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *domain.JobReport) error {
+	return conn(ctx, r.db).Create(report).Error
+}
+
+func (r *reportRepository) CountOpenByJob(ctx context.Context, jobID string) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.JobReport{}).Where("job_id = ? AND resolved = ?", jobID, false).Count(&total).Error
+	return total, err
+}
+
+func (r *reportRepository) FindOpenByJobs(ctx context.Context, jobIDs []uuid.UUID) ([]domain.JobReport, error) {
+	var reports []domain.JobReport
+	if len(jobIDs) == 0 {
+		return reports, nil
+	}
+	err := conn(ctx, r.db).Where("job_id IN ? AND resolved = ?", jobIDs, false).Order("created_at ASC").Find(&reports).Error
+	return reports, err
+}
+
+func (r *reportRepository) ResolveByJob(ctx context.Context, jobID string) error {
+	return conn(ctx, r.db).Model(&domain.JobReport{}).Where("job_id = ? AND resolved = ?", jobID, false).Update("resolved", true).Error
+}
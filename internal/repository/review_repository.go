@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewRepository stores the notes, ratings and tags companies keep on
+// applications
+type ReviewRepository interface {
+	CreateNote(ctx context.Context, note *domain.ApplicationNote) error
+	FindNote(ctx context.Context, id string) (*domain.ApplicationNote, error)
+	DeleteNote(ctx context.Context, id string) error
+	FindNotes(ctx context.Context, applicationID string) ([]domain.ApplicationNote, error)
+
+	SaveRating(ctx context.Context, rating *domain.ApplicationRating) error
+	FindRatings(ctx context.Context, applicationID string) ([]domain.ApplicationRating, error)
+	RatingSummaries(ctx context.Context, applicationIDs []uuid.UUID) (map[uuid.UUID]domain.RatingSummary, error)
+
+	ReplaceTags(ctx context.Context, applicationID uuid.UUID, tags []string) error
+	FindTags(ctx context.Context, applicationIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) CreateNote(ctx context.Context, note *domain.ApplicationNote) error {
+	return conn(ctx, r.db).Create(note).Error
+}
+
+func (r *reviewRepository) FindNote(ctx context.Context, id string) (*domain.ApplicationNote, error) {
+	var note domain.ApplicationNote
+	err := conn(ctx, r.db).Where("id = ?", id).First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *reviewRepository) DeleteNote(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Delete(&domain.ApplicationNote{}, "id = ?", id).Error
+}
+
+// FindNotes returns an application's notes, newest first
+func (r *reviewRepository) FindNotes(ctx context.Context, applicationID string) ([]domain.ApplicationNote, error) {
+	var notes []domain.ApplicationNote
+	err := conn(ctx, r.db).Where("application_id = ?", applicationID).
+		Order("created_at DESC").Find(&notes).Error
+	return notes, err
+}
+
+// SaveRating inserts or replaces the reviewer's rating of an application
+func (r *reviewRepository) SaveRating(ctx context.Context, rating *domain.ApplicationRating) error {
+	rating.UpdatedAt = time.Now()
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "application_id"}, {Name: "reviewer_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "updated_at"}),
+	}).Create(rating).Error
+}
+
+func (r *reviewRepository) FindRatings(ctx context.Context, applicationID string) ([]domain.ApplicationRating, error) {
+	var ratings []domain.ApplicationRating
+	err := conn(ctx, r.db).Where("application_id = ?", applicationID).
+		Order("updated_at DESC").Find(&ratings).Error
+	return ratings, err
+}
+
+// RatingSummaries averages the ratings of each application in one query.
+// Applications nobody rated are absent from the result.
+func (r *reviewRepository) RatingSummaries(ctx context.Context, applicationIDs []uuid.UUID) (map[uuid.UUID]domain.RatingSummary, error) {
+	summaries := map[uuid.UUID]domain.RatingSummary{}
+	if len(applicationIDs) == 0 {
+		return summaries, nil
+	}
+	var rows []struct {
+		ApplicationID uuid.UUID
+		Average       float64
+		Count         int
+	}
+	err := conn(ctx, r.db).Model(&domain.ApplicationRating{}).
+		Select("application_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("application_id IN ?", applicationIDs).
+		Group("application_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		average := row.Average
+		summaries[row.ApplicationID] = domain.RatingSummary{Average: &average, Count: row.Count}
+	}
+	return summaries, nil
+}
+
+// ReplaceTags makes tags the full set of an application's tags
+func (r *reviewRepository) ReplaceTags(ctx context.Context, applicationID uuid.UUID, tags []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.ApplicationTag{}, "application_id = ?", applicationID).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		rows := make([]domain.ApplicationTag, 0, len(tags))
+		for _, tag := range tags {
+			rows = append(rows, domain.ApplicationTag{ApplicationID: applicationID, Tag: tag})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// FindTags returns the tags of each application, alphabetically
+func (r *reviewRepository) FindTags(ctx context.Context, applicationIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := map[uuid.UUID][]string{}
+	if len(applicationIDs) == 0 {
+		return tags, nil
+	}
+	var rows []domain.ApplicationTag
+	err := conn(ctx, r.db).Where("application_id IN ?", applicationIDs).Order("tag").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tags[row.ApplicationID] = append(tags[row.ApplicationID], row.Tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepository interface {
+	// Ensure inserts any of the named roles that do not exist yet
+	Ensure(ctx context.Context, names []string) error
+	FindByName(ctx context.Context, name string) (*domain.Role, error)
+	ListNames(ctx context.Context) ([]string, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Ensure(ctx context.Context, names []string) error {
+	roles := make([]domain.Role, 0, len(names))
+	for _, name := range names {
+		roles = append(roles, domain.Role{Name: name})
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&roles).Error
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	err := conn(ctx, r.db).Where("LOWER(name) = ?", strings.ToLower(name)).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) ListNames(ctx context.Context) ([]string, error) {
+	var names []string
+	err := conn(ctx, r.db).Model(&domain.Role{}).Pluck("name", &names).Error
+	return names, err
+}
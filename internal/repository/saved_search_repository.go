@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *domain.SavedSearch) error
+	Update(ctx context.Context, search *domain.SavedSearch) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*domain.SavedSearch, error)
+	FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.SavedSearch, domain.PageInfo, error)
+	CountByApplicant(ctx context.Context, applicantID string) (int64, error)
+	FindByUnsubscribeToken(ctx context.Context, token string) (*domain.SavedSearch, error)
+
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.SavedSearch, error)
+	FindUnsentJobs(ctx context.Context, search *domain.SavedSearch, limit int) ([]domain.Job, error)
+	RecordAlert(ctx context.Context, search *domain.SavedSearch, jobs []domain.Job) error
+}
+
+type savedSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedSearchRepository(db *gorm.DB) SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	return conn(ctx, r.db).Create(search).Error
+}
+
+func (r *savedSearchRepository) Update(ctx context.Context, search *domain.SavedSearch) error {
+	return conn(ctx, r.db).Save(search).Error
+}
+
+func (r *savedSearchRepository) Delete(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Delete(&domain.SavedSearch{}, "id = ?", id).Error
+}
+
+func (r *savedSearchRepository) FindByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	var search domain.SavedSearch
+	err := conn(ctx, r.db).Where("id = ?", id).First(&search).Error
+	if err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (r *savedSearchRepository) FindByApplicant(ctx context.Context, applicantID string, q domain.PageQuery) ([]domain.SavedSearch, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.SavedSearch{}).Where("applicant_id = ?", applicantID)
+	return paginate(db, q, "created_at", savedSearchCursor)
+}
+
+func (r *savedSearchRepository) CountByApplicant(ctx context.Context, applicantID string) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.SavedSearch{}).Where("applicant_id = ?", applicantID).Count(&total).Error
+	return total, err
+}
+
+func (r *savedSearchRepository) FindByUnsubscribeToken(ctx context.Context, token string) (*domain.SavedSearch, error) {
+	var search domain.SavedSearch
+	err := conn(ctx, r.db).Where("unsubscribe_token = ?", token).First(&search).Error
+	if err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// ClaimDue returns up to limit searches whose digest is due and hides them
+// from other replicas for lease, like WebhookRepository.ClaimDue
+func (r *savedSearchRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.SavedSearch, error) {
+	var searches []domain.SavedSearch
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("frequency <> ? AND next_alert_at <= ?", domain.AlertOff, now).
+			Order("next_alert_at ASC").Limit(limit).Find(&searches).Error
+		if err != nil || len(searches) == 0 {
+			return err
+		}
+		ids := make([]uuid.UUID, 0, len(searches))
+		for _, s := range searches {
+			ids = append(ids, s.ID)
+		}
+		return tx.Model(&domain.SavedSearch{}).Where("id IN ?", ids).Update("next_alert_at", now.Add(lease)).Error
+	})
+	return searches, err
+}
+
+// FindUnsentJobs returns the newest published jobs matching search that were
+// posted after it was saved and not sent in an earlier digest
+func (r *savedSearchRepository) FindUnsentJobs(ctx context.Context, search *domain.SavedSearch, limit int) ([]domain.Job, error) {
+	sent := r.db.Model(&domain.SavedSearchJob{}).Select("job_id").Where("saved_search_id = ?", search.ID)
+	db := conn(ctx, r.db).Model(&domain.Job{}).
+		Where("status = ? AND created_at >= ? AND id NOT IN (?)", domain.JobStatusPublished, search.CreatedAt, sent)
+	var jobs []domain.Job
+	err := filterJobs(db, search.Filters()).Order("created_at DESC").Order("id DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// RecordAlert marks jobs as sent for search and stores its next digest time
+func (r *savedSearchRepository) RecordAlert(ctx context.Context, search *domain.SavedSearch, jobs []domain.Job) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if len(jobs) > 0 {
+			sent := make([]domain.SavedSearchJob, 0, len(jobs))
+			for _, job := range jobs {
+				sent = append(sent, domain.SavedSearchJob{SavedSearchID: search.ID, JobID: job.ID, SentAt: time.Now()})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sent).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.SavedSearch{}).Where("id = ?", search.ID).
+			Updates(map[string]interface{}{"next_alert_at": search.NextAlertAt, "last_alert_at": search.LastAlertAt}).Error
+	})
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/yesetoda/Sera_Ale/internal/domain"
 	"gorm.io/gorm"
 )
@@ -11,6 +13,12 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindByID(ctx context.Context, id string) (*domain.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.User, error)
+	List(ctx context.Context, filters map[string]interface{}, q domain.PageQuery) ([]domain.User, domain.PageInfo, error)
+	SetSuspended(ctx context.Context, id string, suspended bool) error
+	UpdateProfile(ctx context.Context, id string, skills []string, location string) error
+	CountByRole(ctx context.Context) (map[string]int64, error)
+	CountSuspended(ctx context.Context) (int64, error)
 	GetDB() *gorm.DB
 }
 
@@ -23,12 +31,12 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).Preload("Role").Where("email = ?", email).First(&user).Error
+	err := conn(ctx, r.db).Preload("Role").Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,13 +45,100 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain
 
 func (r *userRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).Preload("Role").Where("id = ?", id).First(&user).Error
+	err := conn(ctx, r.db).Preload("Role").Where("id = ?", id).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+func (r *userRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.User, error) {
+	var users []domain.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) List(ctx context.Context, filters map[string]interface{}, q domain.PageQuery) ([]domain.User, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.User{})
+	if query, ok := filters["q"]; ok {
+		like := "%" + strings.ToLower(query.(string)) + "%"
+		db = db.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", like, like)
+	}
+	if role, ok := filters["role"]; ok {
+		roleIDs := r.db.Model(&domain.Role{}).Select("id").Where("LOWER(name) = ?", strings.ToLower(role.(string)))
+		db = db.Where("role_id IN (?)", roleIDs)
+	}
+	if suspended, ok := filters["suspended"]; ok {
+		db = db.Where("suspended = ?", suspended)
+	}
+	users, info, err := paginate(db, q, "created_at", userCursor)
+	if err != nil {
+		return nil, info, err
+	}
+	return users, info, r.attachRoles(ctx, users)
+}
+
+// attachRoles loads the Role of each user in a single query
+func (r *userRepository) attachRoles(ctx context.Context, users []domain.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.RoleID)
+	}
+	var roles []domain.Role
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&roles).Error; err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]domain.Role, len(roles))
+	for _, role := range roles {
+		byID[role.ID] = role
+	}
+	for i := range users {
+		users[i].Role = byID[users[i].RoleID]
+	}
+	return nil
+}
+
+func (r *userRepository) SetSuspended(ctx context.Context, id string, suspended bool) error {
+	return conn(ctx, r.db).Model(&domain.User{}).Where("id = ?", id).Update("suspended", suspended).Error
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, id string, skills []string, location string) error {
+	return conn(ctx, r.db).Model(&domain.User{}).Where("id = ?", id).Select("skills", "location").
+		Updates(&domain.User{Skills: skills, Location: location}).Error
+}
+
+func (r *userRepository) CountByRole(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Name  string
+		Count int64
+	}
+	err := conn(ctx, r.db).Model(&domain.User{}).
+		Select("roles.name AS name, COUNT(*) AS count").
+		Joins("JOIN roles ON roles.id = users.role_id").
+		Group("roles.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Name] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *userRepository) CountSuspended(ctx context.Context) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.User{}).Where("suspended = ?", true).Count(&total).Error
+	return total, err
+}
+
 func (r *userRepository) GetDB() *gorm.DB {
 	return r.db
 }
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	FindByID(ctx context.Context, id string) (*domain.Webhook, error)
+	FindByCompany(ctx context.Context, companyID string, q domain.PageQuery) ([]domain.Webhook, domain.PageInfo, error)
+	FindAllByCompany(ctx context.Context, companyID uuid.UUID) ([]domain.Webhook, error)
+	CountByCompany(ctx context.Context, companyID string) (int64, error)
+	Delete(ctx context.Context, id string) error
+
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	FindDelivery(ctx context.Context, webhookID, id string) (*domain.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, webhookID string, q domain.PageQuery) ([]domain.WebhookDelivery, domain.PageInfo, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *domain.Webhook) error {
+	return conn(ctx, r.db).Create(webhook).Error
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id string) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := conn(ctx, r.db).Where("id = ?", id).First(&webhook).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) FindByCompany(ctx context.Context, companyID string, q domain.PageQuery) ([]domain.Webhook, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.Webhook{}).Where("company_id = ?", companyID)
+	return paginate(db, q, "created_at", webhookCursor)
+}
+
+func (r *webhookRepository) FindAllByCompany(ctx context.Context, companyID uuid.UUID) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := conn(ctx, r.db).Where("company_id = ?", companyID).Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) CountByCompany(ctx context.Context, companyID string) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.Webhook{}).Where("company_id = ?", companyID).Count(&total).Error
+	return total, err
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Delete(&domain.Webhook{}, "id = ?", id).Error
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&deliveries).Error
+}
+
+func (r *webhookRepository) FindDelivery(ctx context.Context, webhookID, id string) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := conn(ctx, r.db).Where("id = ? AND webhook_id = ?", id, webhookID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, webhookID string, q domain.PageQuery) ([]domain.WebhookDelivery, domain.PageInfo, error) {
+	db := conn(ctx, r.db).Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	return paginate(db, q, "created_at", deliveryCursor)
+}
+
+// ClaimDue returns up to limit pending deliveries that are due, together with
+// their webhook, and pushes their next attempt lease into the future so other
+// replicas skip them while they are being sent. Rows locked by a concurrent
+// claim are skipped rather than waited for.
+func (r *webhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, now).
+			Order("next_attempt_at ASC").Limit(limit).Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		return tx.Model(&domain.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return deliveries, r.attachWebhooks(ctx, deliveries)
+}
+
+// attachWebhooks loads the Webhook of each delivery in a single query
+func (r *webhookRepository) attachWebhooks(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.WebhookID)
+	}
+	var webhooks []domain.Webhook
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&webhooks).Error; err != nil {
+		return err
+	}
+	byID := make(map[uuid.UUID]*domain.Webhook, len(webhooks))
+	for i := range webhooks {
+		byID[webhooks[i].ID] = &webhooks[i]
+	}
+	for i := range deliveries {
+		deliveries[i].Webhook = byID[deliveries[i].WebhookID]
+	}
+	return nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return conn(ctx, r.db).Model(&domain.WebhookDelivery{}).Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+}
@@ -2,36 +2,128 @@ package service
 
 import (
 	"context"
-	"os"
+	"fmt"
+	"io"
+	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/yesetoda/Sera_Ale/internal/metrics"
+	"github.com/yesetoda/Sera_Ale/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type CloudinaryService interface {
 	UploadPDF(ctx context.Context, file interface{}, publicID string) (string, error)
+	DeletePDF(ctx context.Context, publicID string) error
+	UploadAttachment(ctx context.Context, file interface{}, publicID string) (string, error)
+	DeleteAttachment(ctx context.Context, publicID string) error
+	Ping(ctx context.Context) error
 }
 
+const (
+	resumeFolder     = "resumes"
+	attachmentFolder = "attachments"
+)
+
 type cloudinaryService struct {
 	cld *cloudinary.Cloudinary
 }
 
-func NewCloudinaryService() (CloudinaryService, error) {
-	cld, err := cloudinary.NewFromURL(os.Getenv("CLOUDINARY_URL"))
+func NewCloudinaryService(url string) (CloudinaryService, error) {
+	cld, err := cloudinary.NewFromURL(url)
 	if err != nil {
 		return nil, err
 	}
 	return &cloudinaryService{cld: cld}, nil
 }
 
-func (s *cloudinaryService) UploadPDF(ctx context.Context, file interface{}, publicID string) (string, error) {
+func (s *cloudinaryService) UploadPDF(ctx context.Context, file interface{}, publicID string) (url string, err error) {
+	ctx, span := tracing.Start(ctx, "cloudinary.UploadPDF", attribute.String("storage.public_id", publicID))
+	defer func() { tracing.End(span, err) }()
+	if size, ok := fileSize(file); ok {
+		metrics.ResumeUploadBytes.Observe(float64(size))
+		span.SetAttributes(attribute.Int64("storage.size_bytes", size))
+	}
+	start := time.Now()
 	resp, err := s.cld.Upload.Upload(ctx, file, uploader.UploadParams{
 		PublicID:     publicID,
-		Folder:       "resumes",
+		Folder:       resumeFolder,
 		ResourceType: "raw",
 	})
+	metrics.ResumeUploadDuration.WithLabelValues(metrics.Outcome(err)).Observe(time.Since(start).Seconds())
 	if err != nil {
 		return "", err
 	}
 	return resp.SecureURL, nil
 }
+
+// fileSize reports the size of seekable uploads such as multipart files and
+// leaves the read offset where it was
+func fileSize(file interface{}) (int64, bool) {
+	seeker, ok := file.(io.Seeker)
+	if !ok {
+		return 0, false
+	}
+	pos, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if _, serr := seeker.Seek(pos, io.SeekStart); err != nil || serr != nil {
+		return 0, false
+	}
+	return end - pos, true
+}
+
+// DeletePDF removes a file previously stored with UploadPDF under the same publicID
+func (s *cloudinaryService) DeletePDF(ctx context.Context, publicID string) error {
+	return s.destroy(ctx, resumeFolder, publicID)
+}
+
+// UploadAttachment stores a file attached to an application message. Files
+// are stored as raw resources so they are served back unchanged.
+func (s *cloudinaryService) UploadAttachment(ctx context.Context, file interface{}, publicID string) (url string, err error) {
+	ctx, span := tracing.Start(ctx, "cloudinary.UploadAttachment", attribute.String("storage.public_id", publicID))
+	defer func() { tracing.End(span, err) }()
+	resp, err := s.cld.Upload.Upload(ctx, file, uploader.UploadParams{
+		PublicID:     publicID,
+		Folder:       attachmentFolder,
+		ResourceType: "raw",
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.SecureURL, nil
+}
+
+// DeleteAttachment removes a file previously stored with UploadAttachment
+func (s *cloudinaryService) DeleteAttachment(ctx context.Context, publicID string) error {
+	return s.destroy(ctx, attachmentFolder, publicID)
+}
+
+func (s *cloudinaryService) destroy(ctx context.Context, folder, publicID string) error {
+	resp, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     folder + "/" + publicID,
+		ResourceType: "raw",
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Error.Message != "" {
+		return fmt.Errorf("cloudinary destroy: %s", resp.Error.Message)
+	}
+	return nil
+}
+
+// Ping checks that Cloudinary is reachable and accepts our credentials
+func (s *cloudinaryService) Ping(ctx context.Context) error {
+	resp, err := s.cld.Admin.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if resp.Error.Message != "" {
+		return fmt.Errorf("cloudinary ping: %s", resp.Error.Message)
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,17 +13,18 @@ type JWTService interface {
 
 type jwtService struct {
 	secret string
+	ttl    time.Duration
 }
 
-func NewJWTService() JWTService {
-	return &jwtService{secret: os.Getenv("JWT_SECRET")}
+func NewJWTService(secret string, ttl time.Duration) JWTService {
+	return &jwtService{secret: secret, ttl: ttl}
 }
 
 func (j *jwtService) GenerateToken(userID, role string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"exp":     time.Now().Add(j.ttl).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.secret))
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+const (
+	MailDriverDisabled = "disabled"
+	MailDriverLog      = "log"
+	MailDriverSMTP     = "smtp"
+)
+
+// Mail is a plain-text email
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email. Implementations are selected with MailerOptions.Driver
+// so another provider only needs a new driver here.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+type MailerOptions struct {
+	Driver   string
+	From     string
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// NewMailer returns the mailer for opts.Driver, or nil when email is disabled
+func NewMailer(opts MailerOptions) (Mailer, error) {
+	switch opts.Driver {
+	case MailDriverDisabled, "":
+		return nil, nil
+	case MailDriverLog:
+		return logMailer{}, nil
+	case MailDriverSMTP:
+		from, err := mail.ParseAddress(opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("mail sender %q: %w", opts.From, err)
+		}
+		m := &smtpMailer{addr: net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)), host: opts.Host, from: from}
+		if opts.Username != "" {
+			m.auth = smtp.PlainAuth("", opts.Username, opts.Password, opts.Host)
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", opts.Driver)
+	}
+}
+
+// logMailer logs that an email would have been sent instead of sending it,
+// for development. The body is left out: it can hold unsubscribe tokens and
+// message previews.
+type logMailer struct{}
+
+func (logMailer) Send(ctx context.Context, m Mail) error {
+	slog.InfoContext(ctx, "email", "to", m.To, "subject", m.Subject, "body_bytes", len(m.Body))
+	return nil
+}
+
+type smtpMailer struct {
+	addr string
+	host string
+	from *mail.Address
+	auth smtp.Auth
+}
+
+// Send delivers mail over SMTP, upgrading to TLS when the server offers
+// STARTTLS. The whole exchange is bounded by ctx.
+func (m *smtpMailer) Send(ctx context.Context, msg Mail) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.message(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (m *smtpMailer) message(msg Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,195 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func TestColumn(t *testing.T) {
+	table := map[int]string{
+		0:     "A",
+		1:     "B",
+		25:    "Z",
+		26:    "AA",
+		27:    "AB",
+		51:    "AZ",
+		52:    "BA",
+		701:   "ZZ",
+		702:   "AAA",
+		16383: "XFD", // the last column Excel allows
+	}
+	for i, want := range table {
+		if got := column(i); got != want {
+			t.Errorf("column(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestCleanXML(t *testing.T) {
+	table := map[string]string{
+		"":                   "",
+		"plain":              "plain",
+		"tab\tnewline\ncr\r": "tab\tnewline\ncr\r",
+		"nul\x00bell\x07":    "nulbell",
+		"esc\x1b[0m":         "esc[0m",
+		"<&>\"'":             "<&>\"'",
+		"héllo wörld ✓":      "héllo wörld ✓",
+		"bad\uFFFE\uFFFF":    "bad",
+		"emoji 😀":            "emoji 😀",
+	}
+	for in, want := range table {
+		if got := cleanXML(in); got != want {
+			t.Errorf("cleanXML(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDefuseFormula(t *testing.T) {
+	table := map[string]string{
+		"":                     "",
+		"Jane Doe":             "Jane Doe",
+		"=HYPERLINK(\"x\")":    "'=HYPERLINK(\"x\")",
+		"+1":                   "'+1",
+		"-1":                   "'-1",
+		"@SUM(A1)":             "'@SUM(A1)",
+		"\t=1":                 "'\t=1",
+		"\r=1":                 "'\r=1",
+		"a=b":                  "a=b",
+		"'already quoted":      "'already quoted",
+		"https://example.com/": "https://example.com/",
+	}
+	for in, want := range table {
+		if got := defuseFormula(in); got != want {
+			t.Errorf("defuseFormula(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var out bytes.Buffer
+	w := NewCSV(&out)
+	for _, row := range [][]string{
+		{"name", "note"},
+		{"=1+1", "a,b"},
+		{"-2", "say \"hi\""},
+	} {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "name,note\n'=1+1,\"a,b\"\n'-2,\"say \"\"hi\"\"\"\n"
+	if got := out.String(); got != want {
+		t.Errorf("CSV output = %q, want %q", got, want)
+	}
+}
+
+type sheetDoc struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R       string  `xml:"r,attr"`
+			T       string  `xml:"t,attr"`
+			Text    string  `xml:"is>t"`
+			Formula *string `xml:"f"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSX(t *testing.T) {
+	rows := [][]string{
+		{"name", "note"},
+		{"=1+1", "<b>&amp;</b>"},
+		{"ctrl\x00\x07", "line\nbreak"},
+	}
+	var out bytes.Buffer
+	w, err := NewXLSX(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("workbook is not a zip file: %v", err)
+	}
+	parts := map[string][]byte{}
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = body
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		body, ok := parts[name]
+		if !ok {
+			t.Errorf("workbook has no %s", name)
+			continue
+		}
+		if err := xml.Unmarshal(body, new(struct{})); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", name, err)
+		}
+	}
+
+	var sheet sheetDoc
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("sheet is not well-formed XML: %v", err)
+	}
+	want := [][]string{
+		{"name", "note"},
+		// Inline strings are never evaluated, so formulas are kept as typed.
+		{"=1+1", "<b>&amp;</b>"},
+		{"ctrl", "line\nbreak"},
+	}
+	if len(sheet.Rows) != len(want) {
+		t.Fatalf("sheet has %d rows, want %d", len(sheet.Rows), len(want))
+	}
+	for i, row := range sheet.Rows {
+		if wantRef := string(rune('1' + i)); row.R != wantRef {
+			t.Errorf("row %d has r=%q, want %q", i, row.R, wantRef)
+		}
+		if len(row.Cells) != len(want[i]) {
+			t.Errorf("row %d has %d cells, want %d", i, len(row.Cells), len(want[i]))
+			continue
+		}
+		for j, cell := range row.Cells {
+			if ref := column(j) + row.R; cell.R != ref {
+				t.Errorf("cell %d,%d has r=%q, want %q", i, j, cell.R, ref)
+			}
+			if cell.T != "inlineStr" || cell.Formula != nil {
+				t.Errorf("cell %s is t=%q with formula %v, want an inline string", cell.R, cell.T, cell.Formula != nil)
+			}
+			if cell.Text != want[i][j] {
+				t.Errorf("cell %s = %q, want %q", cell.R, cell.Text, want[i][j])
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	table := map[string]string{"csv": "csv", "CSV": "csv", "xlsx": "xlsx", "Xlsx": "xlsx", "xls": "", "": ""}
+	for name, want := range table {
+		f, ok := Lookup(name)
+		if ok != (want != "") || f.Name != want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", name, f.Name, ok, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/yesetoda/Sera_Ale/internal/domain"
+	"github.com/yesetoda/Sera_Ale/internal/metrics"
+	"github.com/yesetoda/Sera_Ale/internal/repository"
+	"github.com/yesetoda/Sera_Ale/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxBackoff caps the delay between two attempts
+const maxBackoff = 12 * time.Hour
+
+var errBlockedAddress = errors.New("webhook target resolves to a private or loopback address")
+
+// Options configures delivery
+type Options struct {
+	// MaxAttempts is how often a delivery is tried before it is marked failed.
+	MaxAttempts int
+	// RetryBase is the delay after the first failure; it doubles with every
+	// further attempt up to maxBackoff.
+	RetryBase time.Duration
+	// Timeout bounds a single HTTP request.
+	Timeout time.Duration
+	// BatchSize is how many deliveries are claimed per poll.
+	BatchSize int
+	// AllowPrivate permits targets on loopback and private networks, which
+	// is only useful in development.
+	AllowPrivate bool
+}
+
+// Dispatcher sends pending deliveries from the outbox
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	opts   Options
+}
+
+func NewDispatcher(repo repository.WebhookRepository, opts Options) *Dispatcher {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = denyPrivate
+	}
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: opts.Timeout},
+	}
+	return &Dispatcher{repo: repo, client: client, opts: opts}
+}
+
+// Backoff returns the delay before the attempt following attempt number n
+func Backoff(base time.Duration, n int) time.Duration {
+	delay := base
+	for i := 1; i < n && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// Run sends every delivery that is due, one batch at a time, until the outbox
+// has nothing left to send or ctx is cancelled. It is meant to be run
+// periodically from a worker.
+func (d *Dispatcher) Run(ctx context.Context) {
+	// A claimed batch is hidden from other replicas long enough to send it.
+	lease := time.Duration(d.opts.BatchSize)*d.opts.Timeout + time.Minute
+	for ctx.Err() == nil {
+		deliveries, err := d.repo.ClaimDue(ctx, time.Now(), lease, d.opts.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "webhook: failed to claim deliveries", "error", err)
+			}
+			return
+		}
+		for i := range deliveries {
+			d.deliver(ctx, &deliveries[i])
+		}
+		if len(deliveries) < d.opts.BatchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	ctx, span := tracing.Start(ctx, "webhook.Deliver",
+		attribute.String("webhook.event", string(delivery.Event)),
+		attribute.String("webhook.delivery_id", delivery.ID.String()))
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	var status int
+	if delivery.Webhook == nil {
+		err = errors.New("webhook no longer exists")
+	} else {
+		status, err = d.send(ctx, delivery)
+	}
+	if ctx.Err() != nil {
+		// Shutting down: leave the delivery to be retried once its lease
+		// expires instead of counting the interrupted attempt.
+		return
+	}
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	switch {
+	case err == nil:
+		delivery.Status = domain.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Webhook == nil || delivery.Attempts >= d.opts.MaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.NextAttemptAt = now.Add(Backoff(d.opts.RetryBase, delivery.Attempts))
+		delivery.LastError = err.Error()
+	}
+	metrics.WebhookDeliveries.WithLabelValues(string(delivery.Event), metrics.Outcome(err)).Inc()
+	if rerr := d.repo.RecordAttempt(ctx, delivery); rerr != nil {
+		slog.ErrorContext(ctx, "webhook: failed to record delivery attempt", "delivery_id", delivery.ID, "error", rerr)
+	}
+}
+
+// send POSTs the payload and returns the response status. Any status outside
+// 2xx counts as a failure.
+func (d *Dispatcher) send(ctx context.Context, delivery *domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sera-Ale-Webhooks/1.0")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, time.Now().Unix(), body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// denyPrivate refuses connections to addresses inside our own network so a
+// webhook URL cannot be used to reach internal services. It runs after DNS
+// resolution, on the address actually dialled.
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return errBlockedAddress
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package webhook signs and delivers the events companies subscribe to.
+//
+// Events are written to the webhook_deliveries outbox by the app layer and
+// sent from there by the Dispatcher, which retries failed deliveries with
+// exponential backoff.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	EventHeader     = "X-Sera-Ale-Event"
+	DeliveryHeader  = "X-Sera-Ale-Delivery"
+	SignatureHeader = "X-Sera-Ale-Signature"
+)
+
+// NewSecret returns a random signing secret for a new webhook
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the SignatureHeader value for body sent at unix time ts:
+// "t=<ts>,v1=<hex HMAC-SHA256 of "<ts>.<body>">". Receivers recompute the
+// HMAC with their secret and should reject stale timestamps.
+func Sign(secret string, ts int64, body []byte) string {
+	t := strconv.FormatInt(ts, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+// Package worker runs background jobs alongside the HTTP server and stops
+// them together during shutdown.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Group owns a set of long-running workers. Each worker receives a context
+// that is cancelled by Stop and is expected to return promptly afterwards.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in its own goroutine
+func (g *Group) Go(name string, fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("worker panicked", "worker", name, "panic", r)
+			}
+		}()
+		fn(g.ctx)
+	}()
+}
+
+// Every runs fn immediately and then once per interval until the group stops.
+// A run that is still in progress when Stop is called finishes first. A run
+// that panics is logged and the next tick runs as usual.
+func (g *Group) Every(name string, interval time.Duration, fn func(ctx context.Context)) {
+	g.Go(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			runOnce(ctx, name, fn)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// runOnce calls fn, recovering from a panic so a periodic worker survives it
+func runOnce(ctx context.Context, name string, fn func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "worker run panicked", "worker", name, "panic", r)
+		}
+	}()
+	fn(ctx)
+}
+
+// Stop cancels every worker and waits for them to return, giving up when ctx
+// expires
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
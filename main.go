@@ -1,24 +1,37 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 	gsfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "github.com/yesetoda/Sera_Ale/docs" // Swagger docs import
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	"github.com/yesetoda/Sera_Ale/internal/app"
+	"github.com/yesetoda/Sera_Ale/internal/config"
 	"github.com/yesetoda/Sera_Ale/internal/handler"
+	"github.com/yesetoda/Sera_Ale/internal/health"
+	"github.com/yesetoda/Sera_Ale/internal/logging"
+	"github.com/yesetoda/Sera_Ale/internal/metrics"
 	"github.com/yesetoda/Sera_Ale/internal/middleware"
+	"github.com/yesetoda/Sera_Ale/internal/moderation"
+	"github.com/yesetoda/Sera_Ale/internal/policy"
 	"github.com/yesetoda/Sera_Ale/internal/repository"
 	"github.com/yesetoda/Sera_Ale/internal/service"
+	"github.com/yesetoda/Sera_Ale/internal/tracing"
+	"github.com/yesetoda/Sera_Ale/internal/webhook"
+	"github.com/yesetoda/Sera_Ale/internal/worker"
 )
 
 // @title Sera Ale Job Board API
@@ -31,53 +44,193 @@ import (
 // @name Authorization
 // @description Provide your JWT token in the format: Bearer {token}
 func main() {
-	// Load env vars
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system env vars")
+	// `migrate up|down|status` runs the schema migrations and exits. It only
+	// needs the database settings, so it is dispatched before the rest of the
+	// configuration is validated.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrateCommand(context.Background(), os.Args[2:]); err != nil {
+			fatal("migrate", err)
+		}
+		return
 	}
+
+	// Load and validate configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("failed to load configuration", err)
+	}
+	logger, err := logging.New(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		fatal("failed to set up logging", err)
+	}
+	slog.SetDefault(logger)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:      cfg.Tracing.Exporter,
+		ServiceName:   cfg.Tracing.ServiceName,
+		Endpoint:      cfg.Tracing.Endpoint,
+		SamplePercent: cfg.Tracing.SamplePercent,
+	})
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+	slog.Info("effective configuration", "config", strings.Split(strings.TrimSpace(cfg.Redacted()), "\n"))
+
 	// Connect to DB
-	dsn := os.Getenv("DATABASE_URL")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError maps driver errors such as unique violations onto
+	// gorm.ErrDuplicatedKey; failed and slow statements are logged through slog
+	db, err := gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{
+		TranslateError: true,
+		Logger:         logging.GormLogger{SlowThreshold: time.Duration(cfg.Log.SlowQuery)},
+	})
 	if err != nil {
-		log.Fatal("failed to connect database: ", err)
+		fatal("failed to connect database", err)
 	}
 	// Ping DB
 	sqlDB, _ := db.DB()
 	if err := sqlDB.Ping(); err != nil {
-		log.Fatal("failed to ping database: ", err)
+		fatal("failed to ping database", err)
+	}
+
+	// Query timings and connection pool statistics for /metrics, and a
+	// span per statement
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		fatal("failed to register query metrics", err)
 	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		fatal("failed to register query tracing", err)
+	}
+	if err := metrics.RegisterDB(sqlDB, "sera_ale"); err != nil {
+		fatal("failed to register pool metrics", err)
+	}
+
+	if cfg.Database.MigrateOnStart {
+		if err := migrateOnStart(context.Background(), sqlDB); err != nil {
+			fatal("migrate", err)
+		}
+	}
+
+	// Background workers, stopped during graceful shutdown
+	workers := worker.NewGroup()
 
 	// Dependency injection
 	userRepo := repository.NewUserRepository(db)
 	jobRepo := repository.NewJobRepository(db)
 	appRepo := repository.NewApplicationRepository(db)
-	jwtSvc := service.NewJWTService()
+	roleRepo := repository.NewRoleRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	savedSearchRepo := repository.NewSavedSearchRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	interviewRepo := repository.NewInterviewRepository(db)
+	messageRepo := repository.NewMessageRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	jwtSvc := service.NewJWTService(cfg.JWT.Secret, time.Duration(cfg.JWT.TTL))
 	pwdSvc := service.NewPasswordService()
-	cloudSvc, err := service.NewCloudinaryService()
+	linkSigner := service.NewLinkSigner(cfg.JWT.Secret)
+	cloudSvc, err := service.NewCloudinaryService(cfg.Cloudinary.URL)
+	if err != nil {
+		fatal("failed to init cloudinary", err)
+	}
+	mailer, err := service.NewMailer(service.MailerOptions{
+		Driver:   cfg.Mail.Driver,
+		From:     cfg.Mail.From,
+		Host:     cfg.Mail.SMTPHost,
+		Port:     cfg.Mail.SMTPPort,
+		Username: cfg.Mail.SMTPUsername,
+		Password: cfg.Mail.SMTPPassword,
+	})
 	if err != nil {
-		log.Fatal("failed to init cloudinary: ", err)
+		fatal("failed to init mailer", err)
 	}
 	userApp := app.NewUserApp(userRepo, jwtSvc, pwdSvc)
-	jobApp := app.NewJobApp(jobRepo)
-	appApp := app.NewApplicationApp(appRepo, jobRepo, cloudSvc)
+	bootstrapApp := app.NewBootstrapApp(roleRepo, userRepo, pwdSvc, app.AdminSeed{
+		Name:     cfg.Admin.Name,
+		Email:    cfg.Admin.Email,
+		Password: cfg.Admin.Password,
+	})
+	if err := bootstrapApp.Seed(context.Background()); err != nil {
+		fatal("failed to seed database (have migrations been applied?)", err)
+	}
+	rules := moderation.Rules{
+		BannedKeywords:    cfg.Moderation.BannedKeywords,
+		HoldLinks:         cfg.Moderation.HoldLinks,
+		NewAccountAge:     time.Duration(cfg.Moderation.NewAccountHours) * time.Hour,
+		NewAccountMaxJobs: cfg.Moderation.NewAccountMaxJobs,
+		ReportThreshold:   cfg.Moderation.ReportThreshold,
+	}
+	webhookApp := app.NewWebhookApp(webhookRepo)
+	notificationApp := app.NewNotificationApp(notificationRepo, userRepo, mailer)
+	// Domain events go to company webhooks and to user notifications, in the
+	// same transaction as the change that raised them
+	events := app.Publishers(webhookApp, notificationApp)
+	tx := repository.NewTransactor(db)
+	jobApp := app.NewJobApp(jobRepo, userRepo, rules, tx, events)
+	appApp := app.NewApplicationApp(appRepo, jobRepo, reviewRepo, cloudSvc, tx, events)
+	adminApp := app.NewAdminApp(userRepo, jobRepo, appRepo, tx, events)
+	moderationApp := app.NewModerationApp(jobRepo, reportRepo, rules, tx, events)
+	savedSearchApp := app.NewSavedSearchApp(savedSearchRepo, jobRepo, tx, notificationApp, cfg.Server.PublicURL)
+	bookmarkApp := app.NewBookmarkApp(bookmarkRepo, jobRepo, appRepo)
+	recommendationApp := app.NewRecommendationApp(userRepo, jobRepo, appRepo, savedSearchRepo)
+	exportApp := app.NewExportApp(appRepo, jobRepo, linkSigner, cfg.Server.PublicURL, time.Duration(cfg.Exports.ResumeLinkTTL))
+	feedApp := app.NewFeedApp(jobApp, userRepo, cfg.Server.PublicURL, cfg.Feeds.RemoteCountry)
+	reviewApp := app.NewReviewApp(reviewRepo, appRepo, jobRepo)
+	messageApp := app.NewMessageApp(messageRepo, appRepo, jobRepo, cloudSvc, tx, notificationApp)
+	interviewApp := app.NewInterviewApp(interviewRepo, appRepo, jobRepo, tx, notificationApp, time.Duration(cfg.Interviews.ReminderLead))
+
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.Options{
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		RetryBase:    time.Duration(cfg.Webhooks.RetryBase),
+		Timeout:      time.Duration(cfg.Webhooks.Timeout),
+		BatchSize:    20,
+		AllowPrivate: cfg.Webhooks.AllowPrivate,
+	})
+	workers.Every("webhooks", time.Duration(cfg.Webhooks.PollInterval), dispatcher.Run)
+	workers.Every("notification-email", time.Duration(cfg.Mail.PollInterval), notificationApp.SendPendingEmails)
+	workers.Every("job-alerts", time.Duration(cfg.Alerts.PollInterval), savedSearchApp.SendAlerts)
+	workers.Every("interview-reminders", time.Duration(cfg.Interviews.PollInterval), interviewApp.SendReminders)
+
+	checker := health.NewChecker(time.Duration(cfg.Server.ReadinessTimeout))
+	if err := registerChecks(checker, sqlDB, bootstrapApp, cloudSvc); err != nil {
+		fatal("failed to set up readiness checks", err)
+	}
 
 	userHandler := handler.NewUserHandler(userApp)
-	jobHandler := handler.NewJobHandler(jobApp)
+	jobHandler := handler.NewJobHandler(jobApp, feedApp)
+	feedHandler := handler.NewFeedHandler(feedApp)
 	appHandler := handler.NewApplicationHandler(appApp)
 	authHandler := handler.NewAuthHandler(userApp)
+	adminHandler := handler.NewAdminHandler(adminApp)
+	moderationHandler := handler.NewModerationHandler(moderationApp)
+	webhookHandler := handler.NewWebhookHandler(webhookApp)
+	notificationHandler := handler.NewNotificationHandler(notificationApp)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchApp)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkApp)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationApp)
+	interviewHandler := handler.NewInterviewHandler(interviewApp)
+	messageHandler := handler.NewMessageHandler(messageApp)
+	reviewHandler := handler.NewReviewHandler(reviewApp)
+	exportHandler := handler.NewExportHandler(exportApp)
+	healthHandler := handler.NewHealthHandler(checker, bootstrapApp)
 
-	// Set up Gin
-	r := gin.Default()
+	// Set up Gin with tracing, request IDs, structured access logs and panic
+	// recovery. Tracing comes first so log entries carry the trace ID.
+	r := gin.New()
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName), middleware.RequestID(), middleware.RequestLogger(), middleware.Recovery())
 
 	// CORS middleware (allow all for dev)
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
+	// Request count and latency per route, scraped from /metrics
+	r.Use(middleware.Metrics())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// Swagger docs
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(gsfiles.Handler))
 
@@ -86,14 +239,14 @@ func main() {
 		c.JSON(200, gin.H{
 			"message":   "Welcome to the Sera Ale Job Board API! See /swagger/index.html for documentation.",
 			"docs":      "/swagger/index.html",
-			"endpoints": []string{"/signup", "/login", "/user/me", "/company/jobs", "/applicant/jobs", "/applicant/applications", "/company/applications/job"},
+			"endpoints": []string{"/signup", "/login", "/user/me", "/company/jobs", "/applicant/jobs", "/applicant/applications", "/applicant/saved_searches", "/applicant/saved-jobs", "/applicant/recommendations", "/applicant/interviews", "/company/applications/job", "/company/webhooks", "/notifications", "/messages/unread_count", "/admin/users", "/admin/stats", "/livez", "/readyz"},
 		})
 	})
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	// Health checks: /livez for the process, /readyz for its dependencies
+	r.GET("/health", healthHandler.Health)
+	r.GET("/livez", healthHandler.Livez)
+	r.GET("/readyz", healthHandler.Readyz)
 
 	// Public routes
 	r.POST("/signup", authHandler.Signup)
@@ -102,32 +255,116 @@ func main() {
 	r.POST("/auth/login", authHandler.Login)
 
 	// Auth middleware
-	auth := middleware.AuthMiddleware(os.Getenv("JWT_SECRET"))
+	auth := middleware.AuthMiddleware(cfg.JWT.Secret)
+	active := middleware.RequireActiveAccount(userApp.CheckActive)
+
+	// Public job listing
+	r.GET("/jobs", jobHandler.ListJobs)
 
 	// Company routes
-	r.GET("/jobs", jobHandler.GetJobsByCompany)
 	// Requires Bearer token in Authorization header.
-	company := r.Group("/company", auth, middleware.RequireRole("company"))
-	company.POST("/jobs", jobHandler.CreateJob)
-	company.PUT("/jobs/:id", jobHandler.UpdateJob)
-	company.DELETE("/jobs/:id", jobHandler.DeleteJob)
-	company.GET("/applications/job", appHandler.GetApplicationsForJob)
-	company.PUT("/applications/:id/status", appHandler.UpdateStatus)
+	company := r.Group("/company", auth, active)
+	company.POST("/jobs", middleware.RequirePermission(policy.JobCreate), jobHandler.CreateJob)
+	company.POST("/jobs/import", middleware.RequirePermission(policy.JobCreate), jobHandler.ImportJobs)
+	company.PUT("/jobs/:id", middleware.RequirePermission(policy.JobUpdate), jobHandler.UpdateJob)
+	company.DELETE("/jobs/:id", middleware.RequirePermission(policy.JobDelete), jobHandler.DeleteJob)
+	company.GET("/jobs/:id/applications/export", middleware.RequirePermission(policy.ApplicationRead), exportHandler.ExportApplicants)
+	company.GET("/applications/job", middleware.RequirePermission(policy.ApplicationRead), appHandler.GetApplicationsForJob)
+	company.PUT("/applications/:id/status", middleware.RequirePermission(policy.ApplicationStatus), appHandler.UpdateStatus)
+	company.GET("/applications/:id/review", middleware.RequirePermission(policy.ApplicationReview), reviewHandler.GetReview)
+	company.POST("/applications/:id/notes", middleware.RequirePermission(policy.ApplicationReview), reviewHandler.AddNote)
+	company.DELETE("/applications/:id/notes/:note_id", middleware.RequirePermission(policy.ApplicationReview), reviewHandler.DeleteNote)
+	company.PUT("/applications/:id/rating", middleware.RequirePermission(policy.ApplicationReview), reviewHandler.RateApplication)
+	company.PUT("/applications/:id/tags", middleware.RequirePermission(policy.ApplicationReview), reviewHandler.SetTags)
+	company.POST("/applications/:id/interviews", middleware.RequirePermission(policy.InterviewManage), interviewHandler.ProposeInterview)
+	company.GET("/applications/:id/interviews", middleware.RequirePermission(policy.InterviewRead), interviewHandler.ApplicationInterviews)
+	company.POST("/interviews/:id/reschedule", middleware.RequirePermission(policy.InterviewManage), interviewHandler.RescheduleInterview)
+	company.POST("/interviews/:id/cancel", middleware.RequirePermission(policy.InterviewManage), interviewHandler.CancelInterview)
+	company.POST("/webhooks", middleware.RequirePermission(policy.WebhookManage), webhookHandler.CreateWebhook)
+	company.GET("/webhooks", middleware.RequirePermission(policy.WebhookManage), webhookHandler.ListWebhooks)
+	company.DELETE("/webhooks/:id", middleware.RequirePermission(policy.WebhookManage), webhookHandler.DeleteWebhook)
+	company.GET("/webhooks/:id/deliveries", middleware.RequirePermission(policy.WebhookManage), webhookHandler.Deliveries)
+	company.POST("/webhooks/:id/deliveries/:delivery_id/replay", middleware.RequirePermission(policy.WebhookManage), webhookHandler.Replay)
 
 	// Applicant routes
 	// Requires Bearer token in Authorization header.
-	applicant := r.Group("/applicant", auth, middleware.RequireRole("applicant"))
-	applicant.GET("/jobs", jobHandler.SearchJobs)
-	applicant.GET("/jobs/:id", jobHandler.GetJob)
-	applicant.POST("/applications", appHandler.Apply)
-	applicant.GET("/applications", appHandler.TrackApplications)
+	applicant := r.Group("/applicant", auth, active)
+	applicant.GET("/jobs", middleware.RequirePermission(policy.JobSearch), jobHandler.SearchJobs)
+	applicant.GET("/jobs/:id", middleware.RequirePermission(policy.JobSearch), jobHandler.GetJob)
+	applicant.POST("/jobs/:id/report", middleware.RequirePermission(policy.JobReport), moderationHandler.ReportJob)
+	applicant.POST("/jobs/:id/save", middleware.RequirePermission(policy.JobBookmark), bookmarkHandler.SaveJob)
+	applicant.DELETE("/jobs/:id/save", middleware.RequirePermission(policy.JobBookmark), bookmarkHandler.UnsaveJob)
+	applicant.GET("/saved-jobs", middleware.RequirePermission(policy.JobBookmark), bookmarkHandler.SavedJobs)
+	applicant.GET("/recommendations", middleware.RequirePermission(policy.JobSearch), recommendationHandler.Recommend)
+	applicant.POST("/applications", middleware.RequirePermission(policy.ApplicationCreate), appHandler.Apply)
+	applicant.GET("/applications", middleware.RequirePermission(policy.ApplicationRead), appHandler.TrackApplications)
+	applicant.POST("/applications/:id/withdraw", middleware.RequirePermission(policy.ApplicationWithdraw), appHandler.Withdraw)
+	applicant.POST("/saved_searches", middleware.RequirePermission(policy.SavedSearchManage), savedSearchHandler.CreateSavedSearch)
+	applicant.GET("/saved_searches", middleware.RequirePermission(policy.SavedSearchManage), savedSearchHandler.ListSavedSearches)
+	applicant.PUT("/saved_searches/:id", middleware.RequirePermission(policy.SavedSearchManage), savedSearchHandler.UpdateSavedSearch)
+	applicant.DELETE("/saved_searches/:id", middleware.RequirePermission(policy.SavedSearchManage), savedSearchHandler.DeleteSavedSearch)
+	applicant.GET("/saved_searches/:id/jobs", middleware.RequirePermission(policy.SavedSearchManage), savedSearchHandler.RunSavedSearch)
+	applicant.GET("/interviews", middleware.RequirePermission(policy.InterviewRespond), interviewHandler.ApplicantInterviews)
+	applicant.POST("/interviews/:id/choose", middleware.RequirePermission(policy.InterviewRespond), interviewHandler.ChooseInterviewSlot)
+	applicant.POST("/interviews/:id/cancel", middleware.RequirePermission(policy.InterviewRespond), interviewHandler.CancelInterview)
+
+	// Unsubscribe link from job alert emails; the token identifies the search
+	r.GET("/alerts/unsubscribe", savedSearchHandler.Unsubscribe)
+
+	// Signed resume links from applicant exports; the signature is the
+	// authentication, so spreadsheet cells can link to resumes
+	r.GET("/resumes/:id", exportHandler.OpenResume)
+
+	// Public job feeds for aggregators
+	r.GET("/jobs/feed.rss", feedHandler.JobsRSS)
+	r.GET("/jobs/feed.atom", feedHandler.JobsAtom)
+	r.GET("/companies/:id/feed.rss", feedHandler.CompanyRSS)
+	r.GET("/companies/:id/feed.atom", feedHandler.CompanyAtom)
 
 	// Public job details route (matches @Router /jobs/{id} [get])
 	r.GET("/jobs/:id", jobHandler.GetJob)
 
 	// User profile route
 	// Requires Bearer token in Authorization header.
-	r.GET("/user/me", auth, userHandler.GetCurrentUser)
+	r.GET("/user/me", auth, active, userHandler.GetCurrentUser)
+	r.PUT("/user/me/profile", auth, active, userHandler.UpdateProfile)
+
+	// Notification inbox and preferences for every role
+	// Requires Bearer token in Authorization header.
+	notifications := r.Group("/notifications", auth, active, middleware.RequirePermission(policy.NotificationRead))
+	notifications.GET("", notificationHandler.Inbox)
+	notifications.GET("/unread_count", notificationHandler.UnreadCount)
+	notifications.POST("/read_all", notificationHandler.MarkAllRead)
+	notifications.POST("/:id/read", notificationHandler.MarkRead)
+	notifications.GET("/preferences", notificationHandler.Preferences)
+	notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+
+	// Interview details and calendar file for the company, the applicant
+	// and admins
+	// Requires Bearer token in Authorization header.
+	interviews := r.Group("/interviews", auth, active, middleware.RequirePermission(policy.InterviewRead))
+	interviews.GET("/:id", interviewHandler.GetInterview)
+	interviews.GET("/:id/ics", interviewHandler.InterviewCalendar)
+
+	// Message threads between an applicant and the company, per application
+	// Requires Bearer token in Authorization header.
+	messages := middleware.RequirePermission(policy.ApplicationMessage)
+	r.GET("/applications/:id/messages", auth, active, messages, messageHandler.Thread)
+	r.POST("/applications/:id/messages", auth, active, messages, messageHandler.SendMessage)
+	r.POST("/applications/:id/messages/read", auth, active, messages, messageHandler.MarkThreadRead)
+	r.GET("/messages/unread_count", auth, active, messages, messageHandler.UnreadMessages)
+
+	// Admin back-office routes
+	// Requires Bearer token in Authorization header.
+	admin := r.Group("/admin", auth, active)
+	admin.GET("/users", middleware.RequirePermission(policy.UserManage), adminHandler.ListUsers)
+	admin.POST("/users/:id/suspend", middleware.RequirePermission(policy.UserManage), adminHandler.SuspendUser)
+	admin.POST("/users/:id/reactivate", middleware.RequirePermission(policy.UserManage), adminHandler.ReactivateUser)
+	admin.POST("/jobs/:id/takedown", middleware.RequirePermission(policy.JobModerate), adminHandler.TakeDownJob)
+	admin.GET("/stats", middleware.RequirePermission(policy.StatsRead), adminHandler.Stats)
+	admin.GET("/moderation/jobs", middleware.RequirePermission(policy.JobModerate), moderationHandler.Queue)
+	admin.POST("/moderation/jobs/:id/approve", middleware.RequirePermission(policy.JobModerate), moderationHandler.Approve)
+	admin.POST("/moderation/jobs/:id/reject", middleware.RequirePermission(policy.JobModerate), moderationHandler.Reject)
 
 	// 404 Not Found handler
 	r.NoRoute(func(c *gin.Context) {
@@ -139,6 +376,27 @@ func main() {
 		c.JSON(405, gin.H{"success": false, "message": "Method not allowed on this route."})
 	})
 
-	fmt.Println("Server running on :8080")
-	r.Run(":8080")
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           r,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeout),
+	}
+	err = serve(srv, workers, sqlDB, checker, time.Duration(cfg.Server.ShutdownDelay), time.Duration(cfg.Server.ShutdownTimeout))
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if ferr := shutdownTracing(flushCtx); ferr != nil {
+		slog.Error("failed to flush traces", "error", ferr)
+	}
+	if err != nil {
+		fatal("server", err)
+	}
+}
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/yesetoda/Sera_Ale/internal/config"
+	"github.com/yesetoda/Sera_Ale/internal/logging"
+	"github.com/yesetoda/Sera_Ale/internal/migrate"
+	"github.com/yesetoda/Sera_Ale/migrations"
+)
+
+const migrateUsage = "usage: sera_ale migrate up|down|status"
+
+// migrateCommand connects with only the database settings and runs the
+// migrate subcommand
+func migrateCommand(ctx context.Context, args []string) error {
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		return err
+	}
+	db, err := gorm.Open(postgres.Open(cfg.URL), &gorm.Config{Logger: logging.GormLogger{}})
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	return runMigrate(ctx, sqlDB, args)
+}
+
+// migrateOnStart applies pending migrations when the server starts,
+// logging each one through slog like the rest of the server output
+func migrateOnStart(ctx context.Context, db *sql.DB) error {
+	runner, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := runner.Up(ctx)
+	for _, m := range applied {
+		slog.InfoContext(ctx, "applied migration", "version", m.Version, "name", m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		slog.InfoContext(ctx, "schema is up to date")
+	}
+	return nil
+}
+
+// runMigrate implements the `migrate up|down|status` subcommands, printing
+// plain text for the operator running them
+func runMigrate(ctx context.Context, db *sql.DB, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+	runner, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		m, err := runner.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %03d_%s\n", m.Version, m.Name)
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\n", st.Version, st.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
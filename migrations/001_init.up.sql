@@ -1,6 +1,7 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
-CREATE TABLE users (
+-- IF NOT EXISTS lets databases created before the migration runner adopt it.
+CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     name VARCHAR(100) NOT NULL,
     email VARCHAR(100) UNIQUE NOT NULL,
@@ -8,7 +9,7 @@ CREATE TABLE users (
     role VARCHAR(20) NOT NULL CHECK (role IN ('applicant', 'company'))
 );
 
-CREATE TABLE jobs (
+CREATE TABLE IF NOT EXISTS jobs (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     title VARCHAR(100) NOT NULL,
     description TEXT NOT NULL,
@@ -17,7 +18,7 @@ CREATE TABLE jobs (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE TABLE applications (
+CREATE TABLE IF NOT EXISTS applications (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     applicant_id UUID REFERENCES users(id),
     job_id UUID REFERENCES jobs(id),
@@ -26,4 +27,4 @@ CREATE TABLE applications (
     status VARCHAR(20) NOT NULL CHECK (status IN ('Applied', 'Reviewed', 'Interview', 'Rejected', 'Hired')),
     applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(applicant_id, job_id)
-); 
\ No newline at end of file
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_job_reports_open;
+DROP TABLE IF EXISTS job_reports;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS hold_reason;
+ALTER TABLE jobs DROP COLUMN IF EXISTS status;
@@ -0,0 +1,17 @@
+-- Moderation state for jobs and the reports applicants file against them.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('published', 'held', 'removed'));
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS hold_reason TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS job_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('scam', 'discriminatory', 'spam', 'other')),
+    details TEXT NOT NULL DEFAULT '',
+    resolved BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT idx_job_reports_job_reporter UNIQUE (job_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_reports_open ON job_reports (job_id) WHERE NOT resolved;
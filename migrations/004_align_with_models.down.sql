@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_applications_applicant_applied_at_id;
+DROP INDEX IF EXISTS idx_applications_job_applied_at_id;
+DROP INDEX IF EXISTS idx_jobs_created_by_created_at_id;
+DROP INDEX IF EXISTS idx_jobs_created_at_id;
+
+ALTER TABLE applications ALTER COLUMN applied_at DROP NOT NULL;
+ALTER TABLE jobs ALTER COLUMN created_at DROP NOT NULL;
+
+-- Admins have no equivalent in the old CHECK constraint and fall back to company.
+ALTER TABLE users ADD COLUMN role VARCHAR(20);
+UPDATE users SET role = CASE roles.name WHEN 'applicant' THEN 'applicant' ELSE 'company' END
+    FROM roles WHERE roles.id = users.role_id;
+ALTER TABLE users ALTER COLUMN role SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('applicant', 'company'));
+ALTER TABLE users DROP COLUMN role_id;
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,32 @@
+-- Brings the schema in line with the GORM models in internal/domain.
+
+-- Roles become a table referenced by users.role_id instead of a VARCHAR column.
+CREATE TABLE IF NOT EXISTS roles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(50) UNIQUE NOT NULL
+);
+
+INSERT INTO roles (name) VALUES ('applicant'), ('company') ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role_id UUID REFERENCES roles(id);
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'role') THEN
+        UPDATE users SET role_id = roles.id FROM roles WHERE roles.name = users.role AND users.role_id IS NULL;
+        ALTER TABLE users DROP COLUMN role;
+    END IF;
+END $$;
+
+ALTER TABLE users ALTER COLUMN role_id SET NOT NULL;
+
+UPDATE jobs SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE jobs ALTER COLUMN created_at SET NOT NULL;
+UPDATE applications SET applied_at = CURRENT_TIMESTAMP WHERE applied_at IS NULL;
+ALTER TABLE applications ALTER COLUMN applied_at SET NOT NULL;
+
+-- Keyset pagination walks (created_at, id) newest first.
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at_id ON jobs (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_by_created_at_id ON jobs (created_by, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_applications_job_applied_at_id ON applications (job_id, applied_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_applications_applicant_applied_at_id ON applications (applicant_id, applied_at DESC, id DESC);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_created_at_id;
+DROP INDEX IF EXISTS idx_webhooks_company_created_at_id;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
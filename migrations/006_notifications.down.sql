@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_email_due;
+DROP INDEX IF EXISTS idx_notifications_inbox;
+
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
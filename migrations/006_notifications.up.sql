@@ -0,0 +1,27 @@
+-- In-app notifications, which also serve as the outbox for notification
+-- emails, and per-user delivery preferences.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',
+    in_inbox BOOLEAN NOT NULL DEFAULT TRUE,
+    read_at TIMESTAMP,
+    email_status VARCHAR(20) NOT NULL DEFAULT 'skipped' CHECK (email_status IN ('pending', 'sent', 'failed', 'skipped')),
+    email_attempts INT NOT NULL DEFAULT 0,
+    email_next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    email BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (user_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_inbox ON notifications (user_id, created_at DESC, id DESC) WHERE in_inbox;
+CREATE INDEX IF NOT EXISTS idx_notifications_email_due ON notifications (email_next_attempt_at) WHERE email_status = 'pending';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_saved_searches_alert_due;
+DROP INDEX IF EXISTS idx_saved_searches_applicant_created_at_id;
+
+DROP TABLE IF EXISTS saved_search_jobs;
+DROP TABLE IF EXISTS saved_searches;
@@ -0,0 +1,26 @@
+-- Applicants' saved job searches and the jobs already sent in their alert
+-- digests.
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    applicant_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    company_name TEXT NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL DEFAULT 'daily' CHECK (frequency IN ('daily', 'weekly', 'off')),
+    unsubscribe_token TEXT NOT NULL UNIQUE,
+    next_alert_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_alert_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS saved_search_jobs (
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (saved_search_id, job_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_applicant_created_at_id ON saved_searches (applicant_id, created_at DESC, id DESC);
+-- The alert worker polls for searches whose digest is due.
+CREATE INDEX IF NOT EXISTS idx_saved_searches_alert_due ON saved_searches (next_alert_at) WHERE frequency <> 'off';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookmarks_applicant_created_at_id;
+
+DROP TABLE IF EXISTS bookmarks;
@@ -0,0 +1,10 @@
+-- Jobs applicants saved to apply to later.
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    applicant_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (applicant_id, job_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_applicant_created_at_id ON bookmarks (applicant_id, created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_interviews_reminder_due;
+DROP INDEX IF EXISTS idx_interviews_applicant_created_at_id;
+DROP INDEX IF EXISTS idx_interviews_application;
+
+DROP TABLE IF EXISTS interviews;
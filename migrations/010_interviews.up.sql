@@ -0,0 +1,26 @@
+-- Interviews attached to applications.
+CREATE TABLE IF NOT EXISTS interviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    applicant_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'proposed' CHECK (status IN ('proposed', 'scheduled', 'cancelled')),
+    slots JSONB NOT NULL DEFAULT '[]',
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    location TEXT NOT NULL DEFAULT '',
+    meeting_url TEXT NOT NULL DEFAULT '',
+    interviewers JSONB NOT NULL DEFAULT '[]',
+    notes TEXT NOT NULL DEFAULT '',
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    sequence INT NOT NULL DEFAULT 0,
+    reminder_sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_interviews_application ON interviews (application_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_interviews_applicant_created_at_id ON interviews (applicant_id, created_at DESC, id DESC);
+-- The reminder worker polls for scheduled interviews without a reminder.
+CREATE INDEX IF NOT EXISTS idx_interviews_reminder_due ON interviews (starts_at) WHERE status = 'scheduled' AND reminder_sent_at IS NULL;
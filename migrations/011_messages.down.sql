@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_messages_recipient_unread;
+DROP INDEX IF EXISTS idx_messages_application_created_at_id;
+
+DROP TABLE IF EXISTS messages;
+
+-- Withdrawn has no equivalent in the old CHECK constraint and falls back to
+-- rejected.
+UPDATE applications SET status = 'Rejected' WHERE status = 'Withdrawn';
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
+ALTER TABLE applications ADD CONSTRAINT applications_status_check
+    CHECK (status IN ('Applied', 'Reviewed', 'Interview', 'Rejected', 'Hired'));
@@ -0,0 +1,21 @@
+-- Applicants can withdraw their applications.
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_status_check;
+ALTER TABLE applications ADD CONSTRAINT applications_status_check
+    CHECK (status IN ('Applied', 'Reviewed', 'Interview', 'Rejected', 'Hired', 'Withdrawn'));
+
+-- Message threads between an applicant and the company, one per application.
+CREATE TABLE IF NOT EXISTS messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL DEFAULT '',
+    attachment_url TEXT NOT NULL DEFAULT '',
+    attachment_name TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_application_created_at_id ON messages (application_id, created_at DESC, id DESC);
+-- Unread counts look up a user's unread messages.
+CREATE INDEX IF NOT EXISTS idx_messages_recipient_unread ON messages (recipient_id, application_id) WHERE read_at IS NULL;
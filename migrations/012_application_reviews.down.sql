@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_application_tags_tag;
+DROP TABLE IF EXISTS application_tags;
+
+DROP TABLE IF EXISTS application_ratings;
+
+DROP INDEX IF EXISTS idx_application_notes_application;
+DROP TABLE IF EXISTS application_notes;
@@ -0,0 +1,28 @@
+-- Private notes, ratings and tags companies keep on applications.
+CREATE TABLE IF NOT EXISTS application_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_notes_application ON application_notes (application_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS application_ratings (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, reviewer_id)
+);
+
+CREATE TABLE IF NOT EXISTS application_tags (
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, tag)
+);
+
+-- Filtering a job's applications by tag starts from the tag.
+CREATE INDEX IF NOT EXISTS idx_application_tags_tag ON application_tags (tag, application_id);
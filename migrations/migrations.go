@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations. Files are named
+// NNN_description.up.sql and NNN_description.down.sql and applied in version
+// order by internal/migrate.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
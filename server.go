@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yesetoda/Sera_Ale/internal/health"
+	"github.com/yesetoda/Sera_Ale/internal/worker"
+)
+
+// serve runs srv until SIGINT or SIGTERM, then shuts down in order: fail
+// readiness and keep serving for drainDelay, stop accepting connections and
+// drain in-flight requests, stop background workers, and close the database
+// pool. Everything after the delay shares one deadline.
+func serve(srv *http.Server, workers *worker.Group, db *sql.DB, checker *health.Checker, drainDelay, drainTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("server running", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+	checker.Drain()
+	if drainDelay > 0 {
+		slog.Info("shutting down, failing readiness before draining", "delay", drainDelay.String())
+		time.Sleep(drainDelay)
+	}
+	slog.Info("shutting down, draining in-flight requests", "timeout", drainTimeout.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	var errs []error
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := db.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	slog.Info("shutdown complete")
+	return nil
+}